@@ -2,43 +2,31 @@ package main
 
 import (
 	"github.com/asyncapi/converter-go/internal/cli"
-	"github.com/docopt/docopt-go"
 
-	v2 "github.com/asyncapi/converter-go/pkg/converter/v2"
-
-	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 const version = "asyncapi-converter 0.2"
 
 func main() {
-	usage := fmt.Sprintf(`
-  Convert AsyncAPI documents from version 1.x to %s. 
-
-  Usage:
-    asyncapi-converter <PATH> [--toYAML] [--id=<id>]
-    asyncapi-converter -h | --help | --version
-
-  Arguments:
-    PATH        a path to asyncapi document (either url or local file, supports json and yaml format)  	
-
-  Options:
-    --toYAML    produces results in yaml format instead json
-    --id=<id>   allows to specify application id`, v2.AsyncapiVersion)
-
-	opts, err := docopt.ParseArgs(usage, nil, version)
-	if err != nil {
-		log.Fatal(err)
-	}
-	asyncapiCli := cli.New(opts)
-	converter, reader, err := asyncapiCli.NewConverterAndReader()
-	if err != nil {
-		log.Fatal(err)
+	stop := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		close(stop)
+	}()
+
+	app := cli.App{
+		Version: version,
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+		Stop:    stop,
 	}
-	err = converter.Convert(reader, os.Stdout)
-	if err != nil {
+	if err := app.Run(os.Args[1:]); err != nil {
 		log.Fatal(err)
 	}
 }
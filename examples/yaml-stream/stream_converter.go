@@ -0,0 +1,48 @@
+package main
+
+import (
+	v2 "github.com/asyncapi/converter-go/pkg/converter/v2"
+	"github.com/asyncapi/converter-go/pkg/decode"
+	"github.com/asyncapi/converter-go/pkg/encode"
+
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	reader := strings.NewReader(documents)
+
+	// create converter of yaml streams
+	converter, err := v2.NewStream()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// convert every document of the stream
+	err = converter.ConvertStream(decode.NewYamlStream(reader), encode.NewYamlStream(os.Stdout))
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+var documents = `asyncapi: 1.2.0
+info:
+  title: Users
+  version: 1.0.0
+topics:
+  user.signedup:
+    publish:
+      payload:
+        type: object
+---
+asyncapi: 1.2.0
+info:
+  title: Orders
+  version: 1.0.0
+topics:
+  order.created:
+    subscribe:
+      payload:
+        type: object
+`
@@ -20,8 +20,8 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// create yaml to json converter
-	converter, err := v2.New(decode.FromYaml, encode.ToJSON)
+	// create yaml to json converter writing the document indented with 2 spaces
+	converter, err := v2.New(decode.FromYaml, encode.JSON(encode.WithIndent(2)))
 	if err != nil {
 		log.Fatal(err)
 	}
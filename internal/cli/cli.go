@@ -4,15 +4,20 @@ import (
 	"github.com/docopt/docopt-go"
 	"github.com/pkg/errors"
 
+	"github.com/asyncapi/converter-go/internal/server"
+	"github.com/asyncapi/converter-go/internal/watch"
 	v2 "github.com/asyncapi/converter-go/pkg/converter/v2"
 	"github.com/asyncapi/converter-go/pkg/decode"
 	asyncapiEncode "github.com/asyncapi/converter-go/pkg/encode"
 
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
+	"time"
 )
 
 var (
@@ -22,8 +27,26 @@ var (
 
 const (
 	optionEncodeYAML = "--toYAML"
-	optionFilePath = "<PATH>"
-	optionID       = "--id"
+	optionEncodeJSON = "--toJSON"
+	optionCanonical  = "--canonical"
+	optionFilePath   = "<PATH>"
+	optionID         = "--id"
+	optionAddr       = "--addr"
+	optionMaxBytes   = "--max-bytes"
+	optionMaxDepth   = "--max-depth"
+	optionMaxAliases = "--max-alias-expansions"
+	optionTimeout    = "--timeout"
+	optionHeader     = "--header"
+	optionInsecure   = "--insecure"
+	optionCACert     = "--ca-cert"
+	optionConfig     = "--config"
+	optionOutDir     = "--out-dir"
+	optionInterval   = "--interval"
+
+	defaultAddr = ":8080"
+
+	// canonicalIndent is the indentation of the canonical output.
+	canonicalIndent = 2
 )
 
 type encode = func(interface{}, io.Writer) error
@@ -31,21 +54,75 @@ type encode = func(interface{}, io.Writer) error
 // Converter converts an AsyncAPI document.
 type Converter interface {
 	Convert(reader io.Reader, writer io.Writer) error
+	ConvertContext(ctx context.Context, reader io.Reader, writer io.Writer) error
 }
 
-var _ v2.Converter = Converter(nil)
+var _ v2.ContextConverter = Converter(nil)
 
 // Cli is a helper type that allows you to instantiate the AsyncAPI Converter and io.Reader of
 // the converted document with arguments passed from the terminal.
 type Cli struct {
 	docopt.Opts
+	fetcher  Fetcher
+	config   *Config
+	warnings io.Writer
+	ctx      context.Context
+}
+
+// Option is a functional option that allows you to configure the Cli.
+type Option func(*Cli)
+
+// WithFetcher is a functional option that allows you to specify the Fetcher used to read documents from URLs.
+// By default, the Cli uses the HTTPFetcher configured with the arguments passed from the terminal.
+func WithFetcher(fetcher Fetcher) Option {
+	return func(cli *Cli) {
+		cli.fetcher = fetcher
+	}
+}
+
+// WithConfig is a functional option that allows you to specify the configuration.
+// By default, the Cli reads the configuration file passed with the --config option
+// or the DefaultConfigFile from the working directory, if it exists.
+func WithConfig(config *Config) Option {
+	return func(cli *Cli) {
+		cli.config = config
+	}
+}
+
+// WithWarnings is a functional option that allows you to specify the writer the conversion warnings are printed to.
+// By default, the warnings are discarded.
+func WithWarnings(writer io.Writer) Option {
+	return func(cli *Cli) {
+		cli.warnings = writer
+	}
+}
+
+// WithContext is a functional option that allows you to specify the context of fetching and converting documents,
+// so the deadlines and the cancellation propagate to the HTTP requests and the conversion.
+// By default, the Cli uses context.Background.
+func WithContext(ctx context.Context) Option {
+	return func(cli *Cli) {
+		cli.ctx = ctx
+	}
 }
 
 // New returns a new Cli instance.
-func New(opts docopt.Opts) Cli {
-	return Cli{
+func New(opts docopt.Opts, options ...Option) Cli {
+	cli := Cli{
 		Opts: opts,
 	}
+	for _, option := range options {
+		option(&cli)
+	}
+	return cli
+}
+
+// Context returns the context of fetching and converting documents, see WithContext.
+func (h Cli) Context() context.Context {
+	if h.ctx == nil {
+		return context.Background()
+	}
+	return h.ctx
 }
 
 func (h Cli) id() *string {
@@ -57,18 +134,166 @@ func (h Cli) id() *string {
 	return &id
 }
 
-func (h Cli) encode() (encode, error) {
-	if _, ok := h.Opts[optionEncodeYAML]; !ok {
-		return asyncapiEncode.ToJSON, nil
+// format returns the output format, or an empty string if the output keeps the format of the input document.
+// The --toYAML and --toJSON arguments take precedence over the configuration file.
+func (h Cli) format() (string, error) {
+	toYaml, err := h.flag(optionEncodeYAML)
+	if err != nil {
+		return "", err
+	}
+	toJSON, err := h.flag(optionEncodeJSON)
+	if err != nil {
+		return "", err
 	}
-	toYaml, ok := h.Opts[optionEncodeYAML].(bool)
+	switch {
+	case toYaml && toJSON:
+		return "", errors.Wrapf(errInvalidArgument, "%s and %s", optionEncodeYAML, optionEncodeJSON)
+	case toYaml:
+		return formatYAML, nil
+	case toJSON:
+		return formatJSON, nil
+	default:
+		return h.settings().format(), nil
+	}
+}
+
+// flag returns the value of the boolean option, which is false if the option is not set.
+func (h Cli) flag(option string) (bool, error) {
+	value, ok := h.Opts[option]
+	if !ok || value == nil {
+		return false, nil
+	}
+	flag, ok := value.(bool)
 	if !ok {
-		return nil, errors.Wrap(errInvalidArgument, optionEncodeYAML)
+		return false, errors.Wrap(errInvalidArgument, option)
+	}
+	return flag, nil
+}
+
+// canonical reports whether the output is written in the canonical form, see encode.Canonical.
+func (h Cli) canonical() bool {
+	if canonical, _ := h.Opts[optionCanonical].(bool); canonical {
+		return true
+	}
+	canonical := h.settings().Canonical
+	return canonical != nil && *canonical
+}
+
+// encode returns the encoder of the output format. If the format is empty, the document is encoded
+// in the format detected by the input decoder.
+func (h Cli) encode(format string, input *decode.Auto) encode {
+	var options []asyncapiEncode.Option
+	if h.canonical() {
+		options = append(options, asyncapiEncode.Canonical(), asyncapiEncode.WithIndent(canonicalIndent))
+	}
+	toJSON, toYaml := asyncapiEncode.JSON(options...), asyncapiEncode.YAML(options...)
+	return func(v interface{}, writer io.Writer) error {
+		if format == formatYAML || format == "" && input.Format == decode.FormatYAML {
+			return toYaml(v, writer)
+		}
+		return toJSON(v, writer)
+	}
+}
+
+func (h Cli) paths() []string {
+	switch pathOption := h.Opts[optionFilePath].(type) {
+	case nil:
+		return nil
+	case []string:
+		return pathOption
+	default:
+		return []string{fmt.Sprintf("%v", pathOption)}
+	}
+}
+
+func (h Cli) path() string {
+	paths := h.paths()
+	if len(paths) == 0 {
+		return ""
+	}
+	return paths[0]
+}
+
+// forPath returns a copy of the Cli converting the document stored under the path.
+func (h Cli) forPath(path string) Cli {
+	opts := make(docopt.Opts, len(h.Opts))
+	for key, value := range h.Opts {
+		opts[key] = value
 	}
-	if toYaml {
-		return asyncapiEncode.ToYaml, nil
+	opts[optionFilePath] = path
+	h.Opts = opts
+	return h
+}
+
+func (h Cli) settings() Settings {
+	if h.config == nil {
+		return Settings{}
 	}
-	return asyncapiEncode.ToJSON, nil
+	return h.config.SettingsFor(h.path())
+}
+
+func (h Cli) converterOptions() ([]v2.ConverterOption, error) {
+	id := h.id()
+	if id == nil {
+		var err error
+		id, err = h.settings().id(h.path())
+		if err != nil {
+			return nil, err
+		}
+	}
+	options := []v2.ConverterOption{
+		v2.WithID(id),
+	}
+	serverNamer, err := h.settings().serverNamer()
+	if err != nil {
+		return nil, err
+	}
+	if serverNamer != nil {
+		options = append(options, v2.WithServerNamer(serverNamer))
+	}
+	environments, err := h.settings().serverEnvironments()
+	if err != nil {
+		return nil, err
+	}
+	if environments != nil {
+		options = append(options, v2.WithServerEnvironments(*environments))
+	}
+	channelNameMapper, err := h.settings().channelNameMapper()
+	if err != nil {
+		return nil, err
+	}
+	if channelNameMapper != nil {
+		options = append(options, v2.WithChannelNameMapper(channelNameMapper))
+	}
+	operationIDNamer, err := h.settings().operationIDNamer()
+	if err != nil {
+		return nil, err
+	}
+	if operationIDNamer != nil {
+		options = append(options, v2.WithOperationIDs(operationIDNamer))
+	}
+	if h.settings().protocolBindings() {
+		options = append(options, v2.WithProtocolBindings(h.settings().BindingPrefixes))
+	}
+	if streamChannel := h.settings().StreamChannel; streamChannel != nil {
+		options = append(options, v2.WithStreamChannelNamer(v2.ParseStreamChannelNamer(*streamChannel)))
+	}
+	if discriminator := h.settings().StreamDiscriminator; discriminator != nil {
+		options = append(options, v2.WithStreamDiscriminator(*discriminator))
+	}
+	if liftComponents := h.settings().LiftComponents; liftComponents != nil && *liftComponents {
+		options = append(options, v2.WithComponentLifting())
+	}
+	if dereference := h.settings().Dereference; dereference != nil && *dereference {
+		options = append(options, v2.WithDereferencing())
+	}
+	if h.warnings != nil {
+		path := h.path()
+		options = append(options, v2.WithWarningHandler(func(warning v2.Warning) {
+			fmt.Fprintf(h.warnings, "%s: warning: %s\n", path, warning)
+		}))
+	}
+	return options, nil
 }
 
 func isURL(str string) bool {
@@ -76,15 +301,60 @@ func isURL(str string) bool {
 	return err == nil && u.Scheme != "" && u.Host != ""
 }
 
-func (h Cli) reader() (io.Reader, error) {
-	fileOption := h.Opts[optionFilePath]
-	path := fmt.Sprintf("%v", fileOption)
+func (h Cli) timeout(defaultTimeout time.Duration) (time.Duration, error) {
+	timeoutOption, ok := h.Opts[optionTimeout].(string)
+	if !ok {
+		return defaultTimeout, nil
+	}
+	timeout, err := time.ParseDuration(timeoutOption)
+	if err != nil || timeout <= 0 {
+		return 0, errors.Wrap(errInvalidArgument, optionTimeout)
+	}
+	return timeout, nil
+}
+
+func (h Cli) newFetcher() (Fetcher, error) {
+	if h.fetcher != nil {
+		return h.fetcher, nil
+	}
+	headerOption, _ := h.Opts[optionHeader].([]string)
+	header, err := parseHeaders(headerOption)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := h.timeout(DefaultFetchTimeout)
+	if err != nil {
+		return nil, err
+	}
+	insecure, _ := h.Opts[optionInsecure].(bool)
+	caCert, _ := h.Opts[optionCACert].(string)
+	tlsConfig, err := newTLSConfig(insecure, caCert)
+	if err != nil {
+		return nil, err
+	}
+	return HTTPFetcher{
+		Client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsConfig,
+			},
+		},
+		Header: header,
+	}, nil
+}
+
+func (h Cli) reader() (io.ReadCloser, error) {
+	path := h.path()
 	if isURL(path) {
-		resp, err := http.Get(path)
+		fetcher, err := h.newFetcher()
 		if err != nil {
 			return nil, err
 		}
-		return resp.Body, nil
+		if fetcher, ok := fetcher.(ContextFetcher); ok {
+			return fetcher.FetchContext(h.Context(), path)
+		}
+		return fetcher.Fetch(path)
 	}
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return nil, errors.Wrap(errFileDoesNotExist, path)
@@ -97,15 +367,131 @@ func (h Cli) reader() (io.Reader, error) {
 }
 
 // NewConverterAndReader creates both a converter and a reader of the converted AsyncAPI document.
-func (h Cli) NewConverterAndReader() (Converter, io.Reader, error) {
-	reader, err := h.reader()
+// The caller is responsible for closing the reader.
+//
+// Arguments passed from the terminal take precedence over the settings from the configuration file.
+func (h Cli) NewConverterAndReader() (Converter, io.ReadCloser, error) {
+	h, err := h.loadConfig()
 	if err != nil {
 		return nil, nil, err
 	}
-	encode, err := h.encode()
+	format, err := h.format()
 	if err != nil {
 		return nil, nil, err
 	}
-	converter, err := v2.New(decode.FromJSONWithYamlFallback, encode, v2.WithID(h.id()))
-	return converter, reader, err
+	converter, err := h.newConverter(format)
+	if err != nil {
+		return nil, nil, err
+	}
+	reader, err := h.reader()
+	if err != nil {
+		return nil, nil, err
+	}
+	return converter, reader, nil
+}
+
+// newConverter creates a converter writing the output format. If the format is empty,
+// the converted document keeps the format of the input document.
+func (h Cli) newConverter(format string) (Converter, error) {
+	options, err := h.converterOptions()
+	if err != nil {
+		return nil, err
+	}
+	input := h.decoder(h.path())
+	converter, err := v2.New(input.Decode, h.encode(format, input), options...)
+	if err != nil {
+		return nil, err
+	}
+	return converter.(v2.ContextConverter), nil
+}
+
+// decoder returns the decoder of the document stored under the path, following the decoding settings
+// from the configuration file.
+func (h Cli) decoder(path string) *decode.Auto {
+	strict := h.settings().StrictYAML
+	return &decode.Auto{Path: path, Strict: strict != nil && *strict}
+}
+
+func (h Cli) addr() string {
+	addr, ok := h.Opts[optionAddr].(string)
+	if !ok || addr == "" {
+		return defaultAddr
+	}
+	return addr
+}
+
+func (h Cli) serverOptions() ([]server.Option, error) {
+	var options []server.Option
+	if maxBytesOption, ok := h.Opts[optionMaxBytes].(string); ok {
+		maxBytes, err := strconv.ParseInt(maxBytesOption, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errInvalidArgument, optionMaxBytes)
+		}
+		options = append(options, server.WithMaxBytes(maxBytes))
+	}
+	if maxDepthOption, ok := h.Opts[optionMaxDepth].(string); ok {
+		maxDepth, err := strconv.Atoi(maxDepthOption)
+		if err != nil {
+			return nil, errors.Wrap(errInvalidArgument, optionMaxDepth)
+		}
+		options = append(options, server.WithMaxDepth(maxDepth))
+	}
+	if maxAliasesOption, ok := h.Opts[optionMaxAliases].(string); ok {
+		maxAliases, err := strconv.Atoi(maxAliasesOption)
+		if err != nil {
+			return nil, errors.Wrap(errInvalidArgument, optionMaxAliases)
+		}
+		options = append(options, server.WithMaxAliasExpansions(maxAliases))
+	}
+	timeout, err := h.timeout(server.DefaultTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return append(options, server.WithTimeout(timeout)), nil
+}
+
+// NewServer creates an HTTP server that converts documents on the address passed from the terminal.
+func (h Cli) NewServer() (*http.Server, error) {
+	options, err := h.serverOptions()
+	if err != nil {
+		return nil, err
+	}
+	return server.NewHTTPServer(h.addr(), options...)
+}
+
+// NewWatcher creates a watcher that converts the documents passed from the terminal into the output directory.
+// The conversion results and errors are printed to the log.
+func (h Cli) NewWatcher(log io.Writer) (*watch.Watcher, error) {
+	h, err := h.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	h.warnings = log
+	options := []watch.Option{
+		watch.WithLog(log),
+	}
+	if intervalOption, ok := h.Opts[optionInterval].(string); ok {
+		interval, err := time.ParseDuration(intervalOption)
+		if err != nil {
+			return nil, errors.Wrap(errInvalidArgument, optionInterval)
+		}
+		options = append(options, watch.WithInterval(interval))
+	}
+	outDir, _ := h.Opts[optionOutDir].(string)
+	newConverter := func(path string) (watch.Converter, string, error) {
+		cli := h.forPath(path)
+		format, err := cli.format()
+		if err != nil {
+			return nil, "", err
+		}
+		if format == "" {
+			format = string(decode.FormatFromPath(path))
+		}
+		if format == "" {
+			format = formatJSON
+		}
+		converter, err := cli.newConverter(format)
+		return converter, "." + format, err
+	}
+	return watch.New(h.paths(), outDir, newConverter, options...)
 }
@@ -1,9 +1,13 @@
 package cli
 
 import (
+	"github.com/asyncapi/converter-go/pkg/decode"
 	. "github.com/onsi/gomega"
 
+	"bytes"
+	"io/ioutil"
 	"testing"
+	"time"
 )
 
 func TestCli_id_error(t *testing.T) {
@@ -20,34 +24,94 @@ func TestCli_id_ok(t *testing.T) {
 	g.Expect(id).To(BeNil())
 }
 
-func TestCli_encode_err(t *testing.T) {
-	g := NewWithT(t)
-	_, err := New(map[string]interface{}{
-		optionEncodeYAML: "error",
-	}).encode()
-	g.Expect(err).Should(HaveOccurred())
-}
-
-func TestCli_encode_ToJSON(t *testing.T) {
-	g := NewWithT(t)
-	_, err := New(map[string]interface{}{}).encode()
-	g.Expect(err).ShouldNot(HaveOccurred())
-}
-
-func TestCli_encode_ToYaml_true(t *testing.T) {
-	g := NewWithT(t)
-	_, err := New(map[string]interface{}{
-		optionEncodeYAML: true,
-	}).encode()
-	g.Expect(err).ShouldNot(HaveOccurred())
+func TestCli_format(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      map[string]interface{}
+		expected  string
+		shouldErr bool
+	}{
+		{
+			name:      "invalid toYAML",
+			opts:      map[string]interface{}{optionEncodeYAML: "error"},
+			shouldErr: true,
+		},
+		{
+			name: "input format",
+			opts: map[string]interface{}{},
+		},
+		{
+			name:     "toYAML",
+			opts:     map[string]interface{}{optionEncodeYAML: true},
+			expected: formatYAML,
+		},
+		{
+			name: "toYAML false",
+			opts: map[string]interface{}{optionEncodeYAML: false},
+		},
+		{
+			name:     "toJSON",
+			opts:     map[string]interface{}{optionEncodeYAML: false, optionEncodeJSON: true},
+			expected: formatJSON,
+		},
+		{
+			name:      "invalid toJSON",
+			opts:      map[string]interface{}{optionEncodeJSON: "error"},
+			shouldErr: true,
+		},
+		{
+			name:      "toYAML and toJSON",
+			opts:      map[string]interface{}{optionEncodeYAML: true, optionEncodeJSON: true},
+			shouldErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			format, err := New(test.opts).format()
+			if test.shouldErr {
+				g.Expect(err).Should(HaveOccurred())
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(format).To(Equal(test.expected))
+		})
+	}
 }
 
-func TestCli_encode_ToYaml_false(t *testing.T) {
-	g := NewWithT(t)
-	_, err := New(map[string]interface{}{
-		optionEncodeYAML: false,
-	}).encode()
-	g.Expect(err).ShouldNot(HaveOccurred())
+func TestCli_encode(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		input    decode.Format
+		expected string
+	}{
+		{
+			name:     "json input",
+			input:    decode.FormatJSON,
+			expected: "{\"asyncapi\":\"2.0.0\"}\n",
+		},
+		{
+			name:     "yaml input",
+			input:    decode.FormatYAML,
+			expected: "asyncapi: 2.0.0\n",
+		},
+		{
+			name:     "format takes precedence",
+			format:   formatJSON,
+			input:    decode.FormatYAML,
+			expected: "{\"asyncapi\":\"2.0.0\"}\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			encode := New(map[string]interface{}{}).encode(test.format, &decode.Auto{Format: test.input})
+			var out bytes.Buffer
+			g.Expect(encode(map[string]interface{}{"asyncapi": "2.0.0"}, &out)).To(Succeed())
+			g.Expect(out.String()).To(Equal(test.expected))
+		})
+	}
 }
 
 func TestCli_reader_error_no_path(t *testing.T) {
@@ -88,3 +152,94 @@ func TestIsUrl(t *testing.T) {
 		})
 	}
 }
+
+func TestCli_NewServer(t *testing.T) {
+	g := NewWithT(t)
+	httpServer, err := New(map[string]interface{}{
+		optionAddr:       "127.0.0.1:9000",
+		optionMaxBytes:   "1024",
+		optionMaxDepth:   "32",
+		optionMaxAliases: "100",
+		optionTimeout:    "2m",
+	}).NewServer()
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(httpServer.Handler).ShouldNot(BeNil())
+	g.Expect(httpServer.Addr).To(Equal("127.0.0.1:9000"))
+	g.Expect(httpServer.ReadTimeout).To(Equal(2 * time.Minute))
+	g.Expect(httpServer.WriteTimeout).To(BeNumerically(">", 2*time.Minute))
+}
+
+func TestCli_NewServer_error(t *testing.T) {
+	tests := []struct {
+		name string
+		opts map[string]interface{}
+	}{
+		{
+			name: "invalid max bytes",
+			opts: map[string]interface{}{optionMaxBytes: "many"},
+		},
+		{
+			name: "invalid max depth",
+			opts: map[string]interface{}{optionMaxDepth: "deep"},
+		},
+		{
+			name: "non-positive max alias expansions",
+			opts: map[string]interface{}{optionMaxAliases: "0"},
+		},
+		{
+			name: "invalid timeout",
+			opts: map[string]interface{}{optionTimeout: "never"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := New(test.opts).NewServer()
+			g.Expect(err).Should(HaveOccurred())
+		})
+	}
+}
+
+func TestCli_NewWatcher(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      map[string]interface{}
+		shouldErr bool
+	}{
+		{
+			name: "valid",
+			opts: map[string]interface{}{
+				optionFilePath: []string{"a.yaml", "b.json"},
+				optionOutDir:   "out",
+				optionInterval: "1s",
+			},
+		},
+		{
+			name: "missing output directory",
+			opts: map[string]interface{}{
+				optionFilePath: []string{"a.yaml"},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "invalid interval",
+			opts: map[string]interface{}{
+				optionFilePath: []string{"a.yaml"},
+				optionOutDir:   "out",
+				optionInterval: "often",
+			},
+			shouldErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := New(test.opts).NewWatcher(ioutil.Discard)
+			if test.shouldErr {
+				g.Expect(err).Should(HaveOccurred())
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+		})
+	}
+}
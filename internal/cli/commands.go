@@ -0,0 +1,516 @@
+package cli
+
+import (
+	"github.com/docopt/docopt-go"
+	"github.com/pkg/errors"
+
+	"github.com/asyncapi/converter-go/internal/server"
+	v2 "github.com/asyncapi/converter-go/pkg/converter/v2"
+	"github.com/asyncapi/converter-go/pkg/decode"
+	asyncapiEncode "github.com/asyncapi/converter-go/pkg/encode"
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
+
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ProgramName is the name of the AsyncAPI Converter executable.
+const ProgramName = "asyncapi-converter"
+
+const (
+	commandConvert    = "convert"
+	commandValidate   = "validate"
+	commandCheck      = "check"
+	commandDiff       = "diff"
+	commandServe      = "serve"
+	commandWatch      = "watch"
+	commandCompletion = "completion"
+	commandVersion    = "version"
+
+	optionConvertedPath = "<CONVERTED>"
+	optionShell         = "<SHELL>"
+)
+
+var (
+	errUnknownCommand     = errors.New("unknown command")
+	errConversionRequired = errors.New("document requires conversion")
+	errDocumentsDiffer    = errors.New("documents differ")
+	errUsage              = errors.New("invalid usage")
+)
+
+var documentOptions = `
+  --toYAML                  produces results in yaml format, defaults to the input format
+  --toJSON                  produces results in json format, defaults to the input format
+  --canonical               produces byte-identical results for the same input, with the keys
+                            in the AsyncAPI field order and the numbers and strings normalized
+  --id=<id>                 allows to specify application id
+  --config=<path>           a path to the configuration file, defaults to .asyncapi-converter.yaml
+                            in the working directory`
+
+var fetchOptions = `
+  --header=<header>         an HTTP header sent when fetching a document from a url, for example
+                            "Authorization: Bearer <token>", can be repeated
+  --timeout=<duration>      the timeout of fetching a document from a url, for example 30s
+  --insecure                skips the verification of the TLS certificate when fetching a document
+  --ca-cert=<path>          a path to the PEM encoded CA certificate used to verify the TLS certificate
+                            when fetching a document`
+
+var pathArgument = `
+  PATH                      a path to asyncapi document (either url or local file, supports json and yaml format)`
+
+// command is a subcommand of the AsyncAPI Converter.
+type command struct {
+	name    string
+	summary string
+	usage   string
+	run     func(app App, cli Cli) error
+}
+
+// commands are initialized in init, because the completion command refers to all commands.
+var commands []command
+
+func init() {
+	commands = []command{
+		{
+			name:    commandConvert,
+			summary: fmt.Sprintf("converts an AsyncAPI document to version %s", v2.AsyncapiVersion),
+			usage: `
+Usage:
+  asyncapi-converter convert <PATH> [options]
+  asyncapi-converter convert -h | --help
+
+Arguments:` + pathArgument + `
+
+Options:
+  -h --help                 shows this help` + documentOptions + fetchOptions,
+			run: runConvert,
+		},
+		{
+			name:    commandValidate,
+			summary: "validates that an AsyncAPI document can be converted",
+			usage: `
+Usage:
+  asyncapi-converter validate <PATH> [options]
+  asyncapi-converter validate -h | --help
+
+Arguments:` + pathArgument + `
+
+Options:
+  -h --help                 shows this help
+  --config=<path>           a path to the configuration file, defaults to .asyncapi-converter.yaml
+                            in the working directory` + fetchOptions,
+			run: runValidate,
+		},
+		{
+			name:    commandCheck,
+			summary: fmt.Sprintf("fails if an AsyncAPI document is not in version %s", v2.AsyncapiVersion),
+			usage: `
+Usage:
+  asyncapi-converter check <PATH> [options]
+  asyncapi-converter check -h | --help
+
+Arguments:` + pathArgument + `
+
+Options:
+  -h --help                 shows this help` + fetchOptions,
+			run: runCheck,
+		},
+		{
+			name:    commandDiff,
+			summary: "compares the conversion result of an AsyncAPI document with a converted document",
+			usage: `
+Usage:
+  asyncapi-converter diff <PATH> <CONVERTED> [options]
+  asyncapi-converter diff -h | --help
+
+Arguments:` + pathArgument + `
+  CONVERTED                 a path to the converted document the conversion result is compared with
+
+Options:
+  -h --help                 shows this help
+  --id=<id>                 allows to specify application id
+  --config=<path>           a path to the configuration file, defaults to .asyncapi-converter.yaml
+                            in the working directory` + fetchOptions,
+			run: runDiff,
+		},
+		{
+			name:    commandServe,
+			summary: "starts the AsyncAPI Converter as an HTTP server",
+			usage: `
+Usage:
+  asyncapi-converter serve [options]
+  asyncapi-converter serve -h | --help
+
+Options:
+  -h --help                 shows this help
+  --addr=<addr>             the address the conversion server listens on [default: :8080]
+  --max-bytes=<bytes>       the maximum size of a document accepted by the conversion server
+  --max-depth=<depth>       the maximum nesting depth of a document accepted by the conversion server
+  --max-alias-expansions=<nodes>
+                            the maximum number of yaml nodes decoded through the aliases of a document
+  --timeout=<duration>      the maximum duration of a conversion request, for example 30s`,
+			run: runServe,
+		},
+		{
+			name:    commandWatch,
+			summary: "reconverts AsyncAPI documents whenever they change",
+			usage: `
+Usage:
+  asyncapi-converter watch <PATH>... --out-dir=<dir> [options]
+  asyncapi-converter watch -h | --help
+
+Arguments:
+  PATH                      a path to asyncapi document or a directory containing json and yaml documents
+
+Options:
+  -h --help                 shows this help
+  --out-dir=<dir>           the directory the watched documents are converted into
+  --interval=<duration>     the interval of polling the watched documents for changes [default: 500ms]` + documentOptions,
+			run: runWatch,
+		},
+		{
+			name:    commandCompletion,
+			summary: "generates the shell completion script for bash, zsh or fish",
+			usage: `
+Usage:
+  asyncapi-converter completion <SHELL>
+  asyncapi-converter completion -h | --help
+
+Arguments:
+  SHELL                     the shell to generate the completion script for, one of bash, zsh or fish
+
+Options:
+  -h --help                 shows this help`,
+			run: runCompletion,
+		},
+		{
+			name:    commandVersion,
+			summary: "prints the version of the AsyncAPI Converter",
+			usage: `
+Usage:
+  asyncapi-converter version
+  asyncapi-converter version -h | --help
+
+Options:
+  -h --help                 shows this help`,
+			run: runVersion,
+		},
+	}
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+func usage() string {
+	var commandList strings.Builder
+	for _, cmd := range commands {
+		fmt.Fprintf(&commandList, "\n  %-12s %s", cmd.name, cmd.summary)
+	}
+	return fmt.Sprintf(`Convert AsyncAPI documents from version 1.x to %s.
+
+Usage:
+  asyncapi-converter <command> [<args>...]
+  asyncapi-converter <PATH> [options]
+  asyncapi-converter -h | --help | --version
+
+Commands:%s
+
+Run 'asyncapi-converter <command> --help' for the arguments and options of the command.
+The second form is an alias of the convert command.`, v2.AsyncapiVersion, commandList.String())
+}
+
+// App runs the commands of the AsyncAPI Converter with arguments passed from the terminal.
+type App struct {
+	// Version is printed by the version command and the --version option.
+	Version string
+	// Stdout is the writer the command results are written to.
+	Stdout io.Writer
+	// Stderr is the writer the usage errors and diagnostic messages are written to.
+	Stderr io.Writer
+	// Stop stops the long-running commands, such as serve and watch, and cancels fetching and converting documents, when closed.
+	Stop <-chan struct{}
+	// Options are applied to the Cli of every command.
+	Options []Option
+}
+
+// Run parses the arguments and runs the selected command.
+// Arguments that do not start with a command name are passed to the convert command.
+func (a App) Run(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(a.Stderr, usage())
+		return errUsage
+	}
+	switch args[0] {
+	case "-h", "--help", "help":
+		fmt.Fprintln(a.Stdout, usage())
+		return nil
+	case "--version":
+		return runVersion(a, Cli{})
+	}
+	cmd, ok := findCommand(args[0])
+	if !ok {
+		if strings.HasPrefix(args[0], "-") {
+			fmt.Fprintln(a.Stderr, usage())
+			return errors.Wrap(errUnknownCommand, args[0])
+		}
+		cmd, _ = findCommand(commandConvert)
+		args = append([]string{commandConvert}, args...)
+	}
+	opts, err := a.parse(cmd, args)
+	if err != nil || opts == nil {
+		return err
+	}
+	ctx, cancel := a.context()
+	defer cancel()
+	options := append([]Option{WithWarnings(a.Stderr), WithContext(ctx)}, a.Options...)
+	return cmd.run(a, New(opts, options...))
+}
+
+// context returns the context of the command, canceled when Stop is closed.
+func (a App) context() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if a.Stop != nil {
+		go func() {
+			select {
+			case <-a.Stop:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+	return ctx, cancel
+}
+
+// parse parses the arguments of the command. It returns nil options if the help was printed.
+func (a App) parse(cmd command, args []string) (docopt.Opts, error) {
+	helped := false
+	parser := docopt.Parser{
+		HelpHandler: func(err error, usage string) {
+			if err != nil {
+				fmt.Fprintln(a.Stderr, usage)
+				return
+			}
+			helped = true
+			fmt.Fprintf(a.Stdout, "%s\n\n%s\n", cmd.summary, strings.TrimSpace(usage))
+		},
+	}
+	opts, err := parser.ParseArgs(cmd.usage, args, "")
+	if err != nil {
+		return nil, errors.Wrap(errUsage, cmd.name)
+	}
+	if helped {
+		return nil, nil
+	}
+	return opts, nil
+}
+
+func runConvert(app App, cli Cli) error {
+	converter, reader, err := cli.NewConverterAndReader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return converter.ConvertContext(cli.Context(), reader, app.Stdout)
+}
+
+func runValidate(app App, cli Cli) error {
+	converter, reader, err := cli.NewConverterAndReader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if err := converter.ConvertContext(cli.Context(), reader, ioutil.Discard); err != nil {
+		return err
+	}
+	fmt.Fprintf(app.Stdout, "%s: valid\n", cli.path())
+	return nil
+}
+
+func runCheck(app App, cli Cli) error {
+	converter, reader, err := cli.NewConverterAndReader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	err = converter.ConvertContext(cli.Context(), reader, ioutil.Discard)
+	switch {
+	case asyncapierr.IsDocumentVersionUpToDate(err):
+		fmt.Fprintf(app.Stdout, "%s: up to date\n", cli.path())
+		return nil
+	case err != nil:
+		return err
+	default:
+		return errors.Wrapf(errConversionRequired, "%s: convert it to version %s", cli.path(), v2.AsyncapiVersion)
+	}
+}
+
+func runDiff(app App, cli Cli) error {
+	cli, err := cli.loadConfig()
+	if err != nil {
+		return err
+	}
+	options, err := cli.converterOptions()
+	if err != nil {
+		return err
+	}
+	converter, err := v2.New(cli.decoder(cli.path()).Decode, asyncapiEncode.ToJSON, options...)
+	if err != nil {
+		return err
+	}
+	reader, err := cli.reader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	var result bytes.Buffer
+	if err := converter.(v2.ContextConverter).ConvertContext(cli.Context(), reader, &result); err != nil {
+		return err
+	}
+	actual, err := normalize(decode.FromJSON, &result)
+	if err != nil {
+		return err
+	}
+
+	convertedPath := fmt.Sprintf("%v", cli.Opts[optionConvertedPath])
+	converted, err := os.Open(convertedPath)
+	if err != nil {
+		return err
+	}
+	defer converted.Close()
+	expected, err := normalize(cli.decoder(convertedPath).Decode, converted)
+	if err != nil {
+		return errors.Wrap(err, convertedPath)
+	}
+
+	differences := diff("", expected, actual)
+	if len(differences) == 0 {
+		return nil
+	}
+	for _, difference := range differences {
+		fmt.Fprintln(app.Stdout, difference)
+	}
+	return errors.Wrapf(errDocumentsDiffer, "%d difference(s)", len(differences))
+}
+
+// normalize decodes the document and round-trips it through JSON, so that
+// documents decoded from JSON and YAML can be compared with each other.
+func normalize(decodeFn v2.Decode, reader io.Reader) (interface{}, error) {
+	var document interface{}
+	if err := decodeFn(&document, reader); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+// diff returns the differences between the documents. Each difference is reported with the JSON pointer
+// to the differing value, prefixed with - for values missing in actual, + for values missing in expected
+// and ~ for changed values.
+func diff(pointer string, expected, actual interface{}) []string {
+	expectedMap, expectedIsMap := expected.(map[string]interface{})
+	actualMap, actualIsMap := actual.(map[string]interface{})
+	if expectedIsMap && actualIsMap {
+		var differences []string
+		for _, key := range sortedKeys(expectedMap, actualMap) {
+			childPointer := pointer + "/" + escapePointer(key)
+			expectedValue, inExpected := expectedMap[key]
+			actualValue, inActual := actualMap[key]
+			switch {
+			case !inActual:
+				differences = append(differences, "- "+childPointer)
+			case !inExpected:
+				differences = append(differences, "+ "+childPointer)
+			default:
+				differences = append(differences, diff(childPointer, expectedValue, actualValue)...)
+			}
+		}
+		return differences
+	}
+	expectedSlice, expectedIsSlice := expected.([]interface{})
+	actualSlice, actualIsSlice := actual.([]interface{})
+	if expectedIsSlice && actualIsSlice && len(expectedSlice) == len(actualSlice) {
+		var differences []string
+		for i := range expectedSlice {
+			differences = append(differences, diff(fmt.Sprintf("%s/%d", pointer, i), expectedSlice[i], actualSlice[i])...)
+		}
+		return differences
+	}
+	expectedJSON, _ := json.Marshal(expected)
+	actualJSON, _ := json.Marshal(actual)
+	if bytes.Equal(expectedJSON, actualJSON) {
+		return nil
+	}
+	if pointer == "" {
+		pointer = "/"
+	}
+	return []string{fmt.Sprintf("~ %s: %s != %s", pointer, expectedJSON, actualJSON)}
+}
+
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+func sortedKeys(maps ...map[string]interface{}) []string {
+	keys := make(map[string]struct{})
+	for _, m := range maps {
+		for key := range m {
+			keys[key] = struct{}{}
+		}
+	}
+	var result []string
+	for key := range keys {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func runServe(app App, cli Cli) error {
+	httpServer, err := cli.NewServer()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(app.Stderr, "listening on %s\n", httpServer.Addr)
+	return server.ListenAndServe(cli.Context(), httpServer)
+}
+
+func runWatch(app App, cli Cli) error {
+	watcher, err := cli.NewWatcher(app.Stderr)
+	if err != nil {
+		return err
+	}
+	return watcher.Run(app.Stop)
+}
+
+func runVersion(app App, _ Cli) error {
+	fmt.Fprintln(app.Stdout, app.Version)
+	return nil
+}
+
+func runCompletion(app App, cli Cli) error {
+	shell := fmt.Sprintf("%v", cli.Opts[optionShell])
+	script, err := completion(shell)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(app.Stdout, script)
+	return err
+}
@@ -0,0 +1,277 @@
+package cli
+
+import (
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+const (
+	testInputPath     = "../../pkg/converter/v2/testdata/input/streetlights1.0.0.yaml"
+	testOutputPath    = "../../pkg/converter/v2/testdata/output/streetlights.yaml"
+	testJSONInputPath = "../../pkg/converter/v2/testdata/input/streetlights1.0.0.json"
+	testUpToDatePath  = "../../pkg/converter/v2/testdata/output/streetlights.json"
+	testOtherOutput   = "../../pkg/converter/v2/testdata/output/slack-rtm.yaml"
+	testInvalidInput  = "../../pkg/converter/v2/testdata/input/invalid/streetlights1.0.0_invalid1.json"
+	testVersionString = "asyncapi-converter test"
+)
+
+func runApp(args ...string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+	app := App{
+		Version: testVersionString,
+		Stdout:  &stdout,
+		Stderr:  &stderr,
+	}
+	err := app.Run(args)
+	return stdout.String(), stderr.String(), err
+}
+
+func TestApp_Run(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedStdout string
+		shouldErr      bool
+	}{
+		{
+			name:           "help",
+			args:           []string{"--help"},
+			expectedStdout: "Commands:",
+		},
+		{
+			name:      "no arguments",
+			shouldErr: true,
+		},
+		{
+			name:      "unknown option",
+			args:      []string{"--unknown"},
+			shouldErr: true,
+		},
+		{
+			name:           "version",
+			args:           []string{"version"},
+			expectedStdout: testVersionString,
+		},
+		{
+			name:           "version option",
+			args:           []string{"--version"},
+			expectedStdout: testVersionString,
+		},
+		{
+			name:           "command help",
+			args:           []string{"convert", "--help"},
+			expectedStdout: "asyncapi-converter convert <PATH> [options]",
+		},
+		{
+			name:      "invalid command usage",
+			args:      []string{"diff", testInputPath},
+			shouldErr: true,
+		},
+		{
+			name:           "convert",
+			args:           []string{"convert", testInputPath, "--toYAML", "--id=urn:test"},
+			expectedStdout: "id: urn:test",
+		},
+		{
+			name:           "convert without command name",
+			args:           []string{testInputPath},
+			expectedStdout: "asyncapi: 2.0.0",
+		},
+		{
+			name:           "convert yaml to json",
+			args:           []string{"convert", testInputPath, "--toJSON"},
+			expectedStdout: `"asyncapi":"2.0.0"`,
+		},
+		{
+			name:      "convert to both formats",
+			args:      []string{"convert", testInputPath, "--toYAML", "--toJSON"},
+			shouldErr: true,
+		},
+		{
+			name:           "convert keeps the json format",
+			args:           []string{"convert", testJSONInputPath},
+			expectedStdout: `"asyncapi":"2.0.0"`,
+		},
+		{
+			name:           "convert canonical",
+			args:           []string{"convert", testJSONInputPath, "--canonical"},
+			expectedStdout: "{\n  \"asyncapi\": \"2.0.0\",\n  \"info\": {\n    \"title\": ",
+		},
+		{
+			name:      "convert error",
+			args:      []string{"convert", testInvalidInput},
+			shouldErr: true,
+		},
+		{
+			name:           "validate",
+			args:           []string{"validate", testInputPath},
+			expectedStdout: "valid",
+		},
+		{
+			name:      "validate error",
+			args:      []string{"validate", testInvalidInput},
+			shouldErr: true,
+		},
+		{
+			name:      "check document requiring conversion",
+			args:      []string{"check", testInputPath},
+			shouldErr: true,
+		},
+		{
+			name:           "check up to date document",
+			args:           []string{"check", testUpToDatePath},
+			expectedStdout: "up to date",
+		},
+		{
+			name: "diff equal documents",
+			args: []string{"diff", testInputPath, testOutputPath},
+		},
+		{
+			name:           "diff different documents",
+			args:           []string{"diff", testInputPath, testOtherOutput},
+			expectedStdout: "- /channels/~1",
+			shouldErr:      true,
+		},
+		{
+			name:           "completion",
+			args:           []string{"completion", "bash"},
+			expectedStdout: "complete -o filenames -F _asyncapi_converter asyncapi-converter",
+		},
+		{
+			name:      "completion unsupported shell",
+			args:      []string{"completion", "tcsh"},
+			shouldErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			stdout, stderr, err := runApp(test.args...)
+			if test.shouldErr {
+				g.Expect(err).Should(HaveOccurred())
+			} else {
+				g.Expect(err).ShouldNot(HaveOccurred(), stderr)
+			}
+			g.Expect(stdout).To(ContainSubstring(test.expectedStdout))
+		})
+	}
+}
+
+func TestApp_Run_warnings(t *testing.T) {
+	g := NewWithT(t)
+	file, err := ioutil.TempFile("", "asyncapi-*.json")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(`{"asyncapi": "1.2.0", "security": [{"apiKey": []}], "topics": {}}`)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(file.Close()).To(Succeed())
+
+	_, stderr, err := runApp("convert", file.Name())
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(stderr).To(Equal(file.Name() + ": warning: /security/0/apiKey: dropped reference to undefined security scheme\n"))
+}
+
+func TestApp_Run_watch(t *testing.T) {
+	g := NewWithT(t)
+	dir, err := ioutil.TempDir("", "asyncapi-converter-watch")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	stop := make(chan struct{})
+	close(stop)
+	var stdout, stderr bytes.Buffer
+	app := App{
+		Stdout: &stdout,
+		Stderr: &stderr,
+		Stop:   stop,
+	}
+	err = app.Run([]string{"watch", testInputPath, "--out-dir", dir, "--interval", "1ms"})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	_, err = os.Stat(dir)
+	g.Expect(err).ShouldNot(HaveOccurred())
+}
+
+func TestApp_Run_serve(t *testing.T) {
+	g := NewWithT(t)
+	stop := make(chan struct{})
+	var stdout, stderr bytes.Buffer
+	app := App{
+		Stdout: &stdout,
+		Stderr: &stderr,
+		Stop:   stop,
+	}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- app.Run([]string{"serve", "--addr", "127.0.0.1:0"})
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(stop)
+	g.Eventually(errs, time.Second).Should(Receive(BeNil()))
+	g.Expect(stderr.String()).To(Equal("listening on 127.0.0.1:0\n"))
+}
+
+func TestCompletion(t *testing.T) {
+	tests := []struct {
+		shell    string
+		expected []string
+	}{
+		{
+			shell: "bash",
+			expected: []string{
+				"convert validate check diff serve watch completion version",
+				`convert) flags="--help --toYAML --toJSON --canonical --id`,
+				`serve) flags="--help --addr --max-bytes --max-depth --max-alias-expansions --timeout"`,
+			},
+		},
+		{
+			shell: "zsh",
+			expected: []string{
+				"#compdef asyncapi-converter",
+				"'--id=[allows to specify application id]:value:'",
+				"'--max-alias-expansions=[the maximum number of yaml nodes decoded through the aliases of a document]:value:'",
+			},
+		},
+		{
+			shell: "fish",
+			expected: []string{
+				"-a diff -d",
+				"-n '__fish_seen_subcommand_from serve' -l addr -r",
+				"-n '__fish_seen_subcommand_from serve' -l max-alias-expansions -r -d 'the maximum number",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.shell, func(t *testing.T) {
+			g := NewWithT(t)
+			script, err := completion(test.shell)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			for _, expected := range test.expected {
+				g.Expect(script).To(ContainSubstring(expected))
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	g := NewWithT(t)
+	expected := map[string]interface{}{
+		"a": "1",
+		"b": []interface{}{1.0, 2.0},
+		"c": map[string]interface{}{"d/e": true},
+	}
+	actual := map[string]interface{}{
+		"b": []interface{}{1.0, 3.0},
+		"c": map[string]interface{}{"d/e": true},
+		"f": nil,
+	}
+	g.Expect(diff("", expected, actual)).To(Equal([]string{
+		"- /a",
+		"~ /b/1: 2 != 3",
+		"+ /f",
+	}))
+}
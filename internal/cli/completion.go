@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"github.com/pkg/errors"
+
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	errUnsupportedShell = errors.New("unsupported shell")
+
+	optionRegexp = regexp.MustCompile(`^\s+(?:-\w\s+)?(--[\w-]+)(=<[^>]+>)?(\s+(.*))?$`)
+)
+
+// flag is an option of a command used to generate the shell completion scripts.
+type flag struct {
+	name        string
+	takesValue  bool
+	description string
+}
+
+// flags returns the options listed in the Options section of the command usage.
+// The description of an option too long to share the line with it is read from the next line.
+func (c command) flags() []flag {
+	var flags []flag
+	section := c.usage[strings.Index(c.usage, "Options:"):]
+	lines := strings.Split(section, "\n")
+	for i, line := range lines {
+		matches := optionRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		description := strings.TrimSpace(matches[4])
+		if description == "" && i+1 < len(lines) {
+			description = strings.TrimSpace(lines[i+1])
+		}
+		flags = append(flags, flag{
+			name:        matches[1],
+			takesValue:  matches[2] != "",
+			description: description,
+		})
+	}
+	return flags
+}
+
+func completion(shell string) (string, error) {
+	switch shell {
+	case "bash":
+		return bashCompletion(), nil
+	case "zsh":
+		return zshCompletion(), nil
+	case "fish":
+		return fishCompletion(), nil
+	default:
+		return "", errors.Wrap(errUnsupportedShell, shell)
+	}
+}
+
+func commandNames() []string {
+	var names []string
+	for _, cmd := range commands {
+		names = append(names, cmd.name)
+	}
+	return names
+}
+
+func flagNames(cmd command) []string {
+	var names []string
+	for _, f := range cmd.flags() {
+		names = append(names, f.name)
+	}
+	return names
+}
+
+func bashCompletion() string {
+	var cases strings.Builder
+	for _, cmd := range commands {
+		fmt.Fprintf(&cases, "        %s) flags=%q ;;\n", cmd.name, strings.Join(flagNames(cmd), " "))
+	}
+	return fmt.Sprintf(`# bash completion for %[1]s
+_asyncapi_converter() {
+    local cur flags
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    if [ "$COMP_CWORD" -eq 1 ]; then
+        COMPREPLY=($(compgen -W %[2]q -- "$cur"))
+        return
+    fi
+    case "${COMP_WORDS[1]}" in
+%[3]s    esac
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=($(compgen -W "$flags" -- "$cur"))
+    else
+        COMPREPLY=($(compgen -f -- "$cur"))
+    fi
+}
+complete -o filenames -F _asyncapi_converter %[1]s
+`, ProgramName, strings.Join(commandNames(), " "), cases.String())
+}
+
+func zshCompletion() string {
+	var commandList, cases strings.Builder
+	for _, cmd := range commands {
+		fmt.Fprintf(&commandList, "        %s\n", zshQuote(cmd.name+":"+cmd.summary))
+		var specs []string
+		for _, f := range cmd.flags() {
+			spec := fmt.Sprintf("%s[%s]", f.name, strings.NewReplacer("[", "(", "]", ")").Replace(f.description))
+			if f.takesValue {
+				spec = fmt.Sprintf("%s=[%s]:value:", f.name, strings.NewReplacer("[", "(", "]", ")").Replace(f.description))
+			}
+			specs = append(specs, zshQuote(spec))
+		}
+		specs = append(specs, zshQuote("*:file:_files"))
+		fmt.Fprintf(&cases, "        %s)\n            _arguments %s\n            ;;\n", cmd.name, strings.Join(specs, " "))
+	}
+	return fmt.Sprintf(`#compdef %[1]s
+_asyncapi_converter() {
+    local -a commands
+    commands=(
+%[2]s    )
+    if (( CURRENT == 2 )); then
+        _describe 'command' commands
+        return
+    fi
+    shift words
+    (( CURRENT-- ))
+    case "${words[1]}" in
+%[3]s    esac
+}
+_asyncapi_converter "$@"
+`, ProgramName, commandList.String(), cases.String())
+}
+
+func zshQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+func fishCompletion() string {
+	var script strings.Builder
+	fmt.Fprintf(&script, "# fish completion for %s\n", ProgramName)
+	fmt.Fprintf(&script, "complete -c %s -f\n", ProgramName)
+	for _, cmd := range commands {
+		fmt.Fprintf(&script, "complete -c %s -n '__fish_use_subcommand' -a %s -d %s\n",
+			ProgramName, cmd.name, zshQuote(cmd.summary))
+	}
+	for _, cmd := range commands {
+		condition := fmt.Sprintf("__fish_seen_subcommand_from %s", cmd.name)
+		for _, f := range cmd.flags() {
+			value := ""
+			if f.takesValue {
+				value = " -r"
+			}
+			fmt.Fprintf(&script, "complete -c %s -n '%s' -l %s%s -d %s\n",
+				ProgramName, condition, strings.TrimPrefix(f.name, "--"), value, zshQuote(f.description))
+		}
+		if strings.Contains(cmd.usage, "<PATH>") {
+			fmt.Fprintf(&script, "complete -c %s -n '%s' -F\n", ProgramName, condition)
+		}
+	}
+	return script.String()
+}
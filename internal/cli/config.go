@@ -0,0 +1,322 @@
+package cli
+
+import (
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	v2 "github.com/asyncapi/converter-go/pkg/converter/v2"
+
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// DefaultConfigFile is the name of the configuration file read from the working directory
+// when no configuration file is passed with the --config option.
+const DefaultConfigFile = ".asyncapi-converter.yaml"
+
+const (
+	formatJSON = "json"
+	formatYAML = "yaml"
+)
+
+var (
+	errInvalidConfig       = errors.New("invalid configuration file")
+	errInvalidFormat       = errors.New("invalid format")
+	errInvalidServerNaming = errors.New("invalid server naming")
+	errInvalidEnvironments = errors.New("invalid server environments")
+	errInvalidChannelNames = errors.New("invalid channel naming")
+	errInvalidOperationIDs = errors.New("invalid operation id naming")
+)
+
+// Settings holds the conversion settings that can be defined in the configuration file.
+type Settings struct {
+	// ID is the application ID. It is a text/template that can refer to
+	// the converted document with {{.Path}} and {{.Name}}, for example urn:example:{{.Name}}.
+	ID *string `yaml:"id"`
+	// Format is the output format, either json or yaml. It defaults to the format of the input document.
+	Format *string `yaml:"format"`
+	// ServerNaming is the name of the server naming strategy: default, host, x-name or description.
+	ServerNaming *string `yaml:"serverNaming"`
+	// ServerNameTemplate is the text/template the servers are named with,
+	// for example {{.Protocol}}-{{.Index}}. It takes precedence over ServerNaming.
+	ServerNameTemplate *string `yaml:"serverNameTemplate"`
+	// ServerEnvironments defines how the servers differing only in one part of the URL are converted:
+	// keep, merge or tag.
+	ServerEnvironments *string `yaml:"serverEnvironments"`
+	// ChannelNaming is the name of the topic to channel name mapping: slash-path, keep-dots, kafka, amqp or mqtt.
+	ChannelNaming *string `yaml:"channelNaming"`
+	// Bindings enables the generation of the protocol bindings from the 1.x extensions.
+	Bindings *bool `yaml:"bindings"`
+	// BindingPrefixes maps the extension prefixes to the protocols of the bindings, for example x-kafka-: kafka.
+	// It defaults to v2.DefaultBindingPrefixes.
+	BindingPrefixes map[string]string `yaml:"bindingPrefixes"`
+	// StreamChannel is the name of the channel converted from the stream or events: server-path derives it
+	// from the server URLs, a name starting with x- reads it from the extension, any other value is the channel name.
+	StreamChannel *string `yaml:"streamChannel"`
+	// StreamDiscriminator is the payload property the stream or events messages are split into channels by.
+	StreamDiscriminator *string `yaml:"streamDiscriminator"`
+	// LiftComponents moves the inline messages and payload schemas to the components.
+	LiftComponents *bool `yaml:"liftComponents"`
+	// Dereference replaces the references to the components with the referenced content.
+	Dereference *bool `yaml:"dereference"`
+	// OperationIDs is the naming scheme of the generated operation IDs: camel or snake.
+	OperationIDs *string `yaml:"operationIds"`
+	// StrictYAML rejects the YAML documents with duplicated keys or keys that are not strings.
+	StrictYAML *bool `yaml:"strictYaml"`
+	// Canonical writes the converted documents in the canonical form, see encode.Canonical.
+	Canonical *bool `yaml:"canonical"`
+}
+
+// Override holds the settings applied to the documents matching the Files glob.
+type Override struct {
+	Files    string `yaml:"files"`
+	Settings `yaml:",inline"`
+}
+
+// Config is the configuration file of the AsyncAPI Converter.
+// It defines the default settings and per-file overrides. Overrides are applied
+// in order, so the last matching override takes precedence.
+type Config struct {
+	Settings  `yaml:",inline"`
+	Overrides []Override `yaml:"overrides"`
+}
+
+// LoadConfig reads the configuration file from the path.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil {
+		return nil, errors.Wrapf(errInvalidConfig, "%s: %s", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, errors.Wrap(err, path)
+	}
+	return &config, nil
+}
+
+func (c Config) validate() error {
+	settings := []Settings{c.Settings}
+	for _, override := range c.Overrides {
+		if _, err := path.Match(override.Files, ""); err != nil || override.Files == "" {
+			return errors.Wrapf(errInvalidConfig, "invalid files glob '%s'", override.Files)
+		}
+		settings = append(settings, override.Settings)
+	}
+	for _, item := range settings {
+		if err := item.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s Settings) validate() error {
+	if s.Format != nil && *s.Format != formatJSON && *s.Format != formatYAML {
+		return errors.Wrap(errInvalidFormat, *s.Format)
+	}
+	if s.ID != nil {
+		if _, err := template.New("id").Parse(*s.ID); err != nil {
+			return errors.Wrapf(errInvalidConfig, "id: %s", err)
+		}
+	}
+	if _, err := s.serverNamer(); err != nil {
+		return err
+	}
+	if _, err := s.serverEnvironments(); err != nil {
+		return err
+	}
+	if _, err := s.channelNameMapper(); err != nil {
+		return err
+	}
+	if _, err := s.operationIDNamer(); err != nil {
+		return err
+	}
+	if s.BindingPrefixes != nil {
+		if err := v2.ValidateBindingPrefixes(s.BindingPrefixes); err != nil {
+			return errors.Wrapf(errInvalidConfig, "bindingPrefixes: %s", err)
+		}
+	}
+	return nil
+}
+
+func (s Settings) merge(other Settings) Settings {
+	if other.ID != nil {
+		s.ID = other.ID
+	}
+	if other.Format != nil {
+		s.Format = other.Format
+	}
+	if other.ServerNaming != nil {
+		s.ServerNaming = other.ServerNaming
+	}
+	if other.ServerNameTemplate != nil {
+		s.ServerNameTemplate = other.ServerNameTemplate
+	}
+	if other.ServerEnvironments != nil {
+		s.ServerEnvironments = other.ServerEnvironments
+	}
+	if other.ChannelNaming != nil {
+		s.ChannelNaming = other.ChannelNaming
+	}
+	if other.Bindings != nil {
+		s.Bindings = other.Bindings
+	}
+	if other.BindingPrefixes != nil {
+		s.BindingPrefixes = other.BindingPrefixes
+	}
+	if other.StreamChannel != nil {
+		s.StreamChannel = other.StreamChannel
+	}
+	if other.StreamDiscriminator != nil {
+		s.StreamDiscriminator = other.StreamDiscriminator
+	}
+	if other.LiftComponents != nil {
+		s.LiftComponents = other.LiftComponents
+	}
+	if other.Dereference != nil {
+		s.Dereference = other.Dereference
+	}
+	if other.OperationIDs != nil {
+		s.OperationIDs = other.OperationIDs
+	}
+	if other.StrictYAML != nil {
+		s.StrictYAML = other.StrictYAML
+	}
+	if other.Canonical != nil {
+		s.Canonical = other.Canonical
+	}
+	return s
+}
+
+// SettingsFor returns the default settings merged with all overrides matching the document path.
+// Globs without a path separator are matched against the base name of the document.
+func (c Config) SettingsFor(documentPath string) Settings {
+	settings := c.Settings
+	slashPath := filepath.ToSlash(documentPath)
+	for _, override := range c.Overrides {
+		target := slashPath
+		if !strings.Contains(override.Files, "/") {
+			target = path.Base(slashPath)
+		}
+		if matched, _ := path.Match(override.Files, target); matched {
+			settings = settings.merge(override.Settings)
+		}
+	}
+	return settings
+}
+
+func (s Settings) id(documentPath string) (*string, error) {
+	if s.ID == nil {
+		return nil, nil
+	}
+	tmpl, err := template.New("id").Parse(*s.ID)
+	if err != nil {
+		return nil, err
+	}
+	base := path.Base(filepath.ToSlash(documentPath))
+	var id bytes.Buffer
+	err = tmpl.Execute(&id, struct {
+		Path string
+		Name string
+	}{
+		Path: documentPath,
+		Name: strings.TrimSuffix(base, path.Ext(base)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := id.String()
+	return &result, nil
+}
+
+func (s Settings) serverNamer() (v2.ServerNamer, error) {
+	if s.ServerNameTemplate != nil {
+		namer, err := v2.ServerNameFromTemplate(*s.ServerNameTemplate)
+		if err != nil {
+			return nil, errors.Wrapf(errInvalidConfig, "serverNameTemplate: %s", err)
+		}
+		return namer, nil
+	}
+	if s.ServerNaming != nil {
+		namer, ok := v2.ServerNamerByName(*s.ServerNaming)
+		if !ok {
+			return nil, errors.Wrap(errInvalidServerNaming, *s.ServerNaming)
+		}
+		return namer, nil
+	}
+	return nil, nil
+}
+
+func (s Settings) serverEnvironments() (*v2.ServerEnvironments, error) {
+	if s.ServerEnvironments == nil {
+		return nil, nil
+	}
+	environments, ok := v2.ServerEnvironmentsByName(*s.ServerEnvironments)
+	if !ok {
+		return nil, errors.Wrap(errInvalidEnvironments, *s.ServerEnvironments)
+	}
+	return &environments, nil
+}
+
+func (s Settings) channelNameMapper() (v2.ChannelNameMapper, error) {
+	if s.ChannelNaming == nil {
+		return nil, nil
+	}
+	mapper, ok := v2.ChannelNameMapperByName(*s.ChannelNaming)
+	if !ok {
+		return nil, errors.Wrap(errInvalidChannelNames, *s.ChannelNaming)
+	}
+	return mapper, nil
+}
+
+func (s Settings) operationIDNamer() (v2.OperationIDNamer, error) {
+	if s.OperationIDs == nil {
+		return nil, nil
+	}
+	namer, ok := v2.OperationIDNamerByName(*s.OperationIDs)
+	if !ok {
+		return nil, errors.Wrap(errInvalidOperationIDs, *s.OperationIDs)
+	}
+	return namer, nil
+}
+
+func (s Settings) protocolBindings() bool {
+	return s.Bindings != nil && *s.Bindings
+}
+
+// format returns the output format, or an empty string if the output keeps the format of the input document.
+func (s Settings) format() string {
+	if s.Format != nil {
+		return *s.Format
+	}
+	return ""
+}
+
+func (h Cli) loadConfig() (Cli, error) {
+	if h.config != nil {
+		return h, nil
+	}
+	configPath, ok := h.Opts[optionConfig].(string)
+	if !ok {
+		if _, err := os.Stat(DefaultConfigFile); os.IsNotExist(err) {
+			return h, nil
+		}
+		configPath = DefaultConfigFile
+	}
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		return h, err
+	}
+	h.config = config
+	return h, nil
+}
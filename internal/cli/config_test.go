@@ -0,0 +1,232 @@
+package cli
+
+import (
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testConfig = `id: "urn:example:{{.Name}}"
+format: yaml
+serverNaming: host
+overrides:
+  - files: "*.json"
+    format: json
+  - files: "legacy/*.yaml"
+    id: urn:legacy
+`
+
+func writeTestConfig(t *testing.T, content string) (string, func()) {
+	dir, err := ioutil.TempDir("", "asyncapi-converter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, DefaultConfigFile)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestLoadConfig_error(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "unknown field",
+			content: "unknown: true",
+		},
+		{
+			name:    "invalid format",
+			content: "format: xml",
+		},
+		{
+			name:    "invalid glob",
+			content: "overrides:\n  - files: \"[\"",
+		},
+		{
+			name:    "invalid id template",
+			content: "id: \"{{.Name\"",
+		},
+		{
+			name:    "invalid server naming",
+			content: "serverNaming: random",
+		},
+		{
+			name:    "invalid server environments",
+			content: "serverEnvironments: split",
+		},
+		{
+			name:    "invalid channel naming",
+			content: "channelNaming: dots",
+		},
+		{
+			name:    "invalid binding prefixes",
+			content: "bindingPrefixes:\n  kafka-: kafka",
+		},
+		{
+			name:    "invalid operation ids",
+			content: "operationIds: kebab",
+		},
+		{
+			name:    "invalid server name template",
+			content: "overrides:\n  - files: \"*.json\"\n    serverNameTemplate: \"{{.Host\"",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			path, remove := writeTestConfig(t, test.content)
+			defer remove()
+			_, err := LoadConfig(path)
+			g.Expect(err).Should(HaveOccurred())
+		})
+	}
+}
+
+func TestConfig_SettingsFor(t *testing.T) {
+	path, remove := writeTestConfig(t, testConfig)
+	defer remove()
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path           string
+		expectedID     string
+		expectedFormat string
+	}{
+		{
+			path:           "specs/streetlights.yaml",
+			expectedID:     "urn:example:streetlights",
+			expectedFormat: formatYAML,
+		},
+		{
+			path:           "specs/streetlights.json",
+			expectedID:     "urn:example:streetlights",
+			expectedFormat: formatJSON,
+		},
+		{
+			path:           "legacy/gitter.yaml",
+			expectedID:     "urn:legacy",
+			expectedFormat: formatYAML,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			g := NewWithT(t)
+			settings := config.SettingsFor(test.path)
+			id, err := settings.id(test.path)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(*id).To(Equal(test.expectedID))
+			g.Expect(*settings.Format).To(Equal(test.expectedFormat))
+		})
+	}
+}
+
+func TestCli_NewConverterAndReader_config(t *testing.T) {
+	configPath, remove := writeTestConfig(t, testConfig)
+	defer remove()
+	inputPath := "../../pkg/converter/v2/testdata/input/streetlights1.0.0.json"
+	tests := []struct {
+		name     string
+		opts     map[string]interface{}
+		expected string
+	}{
+		{
+			name: "settings from the configuration file",
+			opts: map[string]interface{}{
+				optionFilePath: inputPath,
+				optionConfig:   configPath,
+			},
+			expected: `"id":"urn:example:streetlights1.0.0"`,
+		},
+		{
+			name: "server naming from the configuration file",
+			opts: map[string]interface{}{
+				optionFilePath: inputPath,
+				optionConfig:   configPath,
+			},
+			expected: `"servers":{"api.streetlights.smartylighting.com"`,
+		},
+		{
+			name: "arguments take precedence",
+			opts: map[string]interface{}{
+				optionFilePath: inputPath,
+				optionConfig:   configPath,
+				optionID:       "urn:argument",
+			},
+			expected: `"id":"urn:argument"`,
+		},
+		{
+			name: "toYAML takes precedence",
+			opts: map[string]interface{}{
+				optionFilePath:   inputPath,
+				optionConfig:     configPath,
+				optionEncodeYAML: true,
+			},
+			expected: "id: urn:example:streetlights1.0.0",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			converter, reader, err := New(test.opts).NewConverterAndReader()
+			g.Expect(err).ShouldNot(HaveOccurred())
+			defer reader.Close()
+			var out bytes.Buffer
+			g.Expect(converter.Convert(reader, &out)).To(Succeed())
+			g.Expect(out.String()).To(ContainSubstring(test.expected))
+		})
+	}
+}
+
+func TestCli_NewConverterAndReader_config_error(t *testing.T) {
+	g := NewWithT(t)
+	_, _, err := New(map[string]interface{}{
+		optionFilePath: "../../pkg/converter/v2/testdata/input/streetlights1.0.0.json",
+		optionConfig:   "/invalid/path/to/a/config",
+	}).NewConverterAndReader()
+	g.Expect(err).Should(HaveOccurred())
+}
+
+func TestCli_NewConverterAndReader_strictYAML(t *testing.T) {
+	configPath, remove := writeTestConfig(t, "strictYaml: true")
+	defer remove()
+	inputPath := filepath.Join(filepath.Dir(configPath), "asyncapi.yaml")
+	input := "asyncapi: 1.2.0\ninfo: {title: a, version: 1.0.0}\ntopics:\n  a: {}\n  a: {}\n"
+	if err := ioutil.WriteFile(inputPath, []byte(input), 0600); err != nil {
+		t.Fatal(err)
+	}
+	g := NewWithT(t)
+	converter, reader, err := New(map[string]interface{}{
+		optionFilePath: inputPath,
+		optionConfig:   configPath,
+	}).NewConverterAndReader()
+	g.Expect(err).ShouldNot(HaveOccurred())
+	defer reader.Close()
+	err = converter.Convert(reader, ioutil.Discard)
+	g.Expect(err).Should(HaveOccurred())
+	g.Expect(err.Error()).To(HaveSuffix(`yaml: line 5: mapping key "a" already defined at line 4`))
+}
+
+func TestApp_Run_diff_strictYAML(t *testing.T) {
+	configPath, remove := writeTestConfig(t, "strictYaml: true")
+	defer remove()
+	inputPath := filepath.Join(filepath.Dir(configPath), "asyncapi.yaml")
+	input := "asyncapi: 1.2.0\ninfo: {title: a, version: 1.0.0}\ntopics:\n  a: {}\n  a: {}\n"
+	if err := ioutil.WriteFile(inputPath, []byte(input), 0600); err != nil {
+		t.Fatal(err)
+	}
+	g := NewWithT(t)
+	_, _, err := runApp("diff", inputPath, testOutputPath, "--config", configPath)
+	g.Expect(err).Should(HaveOccurred())
+	g.Expect(err.Error()).To(HaveSuffix(`yaml: line 5: mapping key "a" already defined at line 4`))
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"github.com/pkg/errors"
+
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultFetchTimeout is the default timeout of fetching a document from a URL.
+const DefaultFetchTimeout = 30 * time.Second
+
+var (
+	errUnexpectedStatus = errors.New("unexpected response status")
+	errInvalidHeader    = errors.New("invalid header")
+	errInvalidCACert    = errors.New("invalid CA certificate")
+)
+
+// Fetcher fetches an AsyncAPI document from a URL.
+type Fetcher interface {
+	Fetch(url string) (io.ReadCloser, error)
+}
+
+// ContextFetcher is a Fetcher that stops fetching when the context is done.
+// The Cli fetches documents with FetchContext if the Fetcher implements it.
+type ContextFetcher interface {
+	Fetcher
+	FetchContext(ctx context.Context, url string) (io.ReadCloser, error)
+}
+
+// HTTPFetcher is a Fetcher that fetches documents with an HTTP client,
+// sending additional headers with every request.
+type HTTPFetcher struct {
+	Client *http.Client
+	Header http.Header
+}
+
+// Fetch sends a GET request to the url and returns the response body.
+// Responses with a status code other than 2xx are rejected.
+func (f HTTPFetcher) Fetch(url string) (io.ReadCloser, error) {
+	return f.FetchContext(context.Background(), url)
+}
+
+// FetchContext works like Fetch, but the request, including reading the response body, is canceled
+// when the context is done.
+func (f HTTPFetcher) FetchContext(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	for key, values := range f.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, errors.Wrapf(errUnexpectedStatus, "%s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func parseHeaders(headers []string) (http.Header, error) {
+	result := make(http.Header)
+	for _, header := range headers {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, errors.Wrap(errInvalidHeader, header)
+		}
+		result.Add(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	}
+	return result, nil
+}
+
+func newTLSConfig(insecure bool, caCertPath string) (*tls.Config, error) {
+	config := tls.Config{
+		InsecureSkipVerify: insecure,
+	}
+	if caCertPath == "" {
+		return &config, nil
+	}
+	caCert, err := ioutil.ReadFile(caCertPath)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(caCert) {
+		return nil, errors.Wrap(errInvalidCACert, caCertPath)
+	}
+	config.RootCAs = pool
+	return &config, nil
+}
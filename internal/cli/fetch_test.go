@@ -0,0 +1,204 @@
+package cli
+
+import (
+	. "github.com/onsi/gomega"
+
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testFetcher func(url string) (io.ReadCloser, error)
+
+func (fetcher testFetcher) Fetch(url string) (io.ReadCloser, error) {
+	return fetcher(url)
+}
+
+func TestHTTPFetcher_Fetch(t *testing.T) {
+	g := NewWithT(t)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("asyncapi: 1.2.0"))
+	}))
+	defer testServer.Close()
+
+	header, err := parseHeaders([]string{"Authorization: Bearer token"})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	body, err := HTTPFetcher{Header: header}.Fetch(testServer.URL)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	defer body.Close()
+	content, err := ioutil.ReadAll(body)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(string(content)).To(Equal("asyncapi: 1.2.0"))
+}
+
+func TestHTTPFetcher_Fetch_status_error(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{
+			name:   "not found",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "internal server error",
+			status: http.StatusInternalServerError,
+		},
+		{
+			name:   "redirect without location",
+			status: http.StatusMultipleChoices,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte("<html></html>"))
+			}))
+			defer testServer.Close()
+
+			_, err := HTTPFetcher{}.Fetch(testServer.URL)
+			g.Expect(err).Should(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring(http.StatusText(test.status)))
+		})
+	}
+}
+
+func TestCli_reader_fetcher(t *testing.T) {
+	g := NewWithT(t)
+	var fetchedURL string
+	fetcher := testFetcher(func(url string) (io.ReadCloser, error) {
+		fetchedURL = url
+		return ioutil.NopCloser(strings.NewReader("test")), nil
+	})
+	reader, err := New(map[string]interface{}{
+		optionFilePath: "https://registry.example.com/schema.yaml",
+	}, WithFetcher(fetcher)).reader()
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(reader.Close()).To(Succeed())
+	g.Expect(fetchedURL).To(Equal("https://registry.example.com/schema.yaml"))
+}
+
+type testContextFetcher struct {
+	testFetcher
+	ctx context.Context
+}
+
+func (fetcher *testContextFetcher) FetchContext(ctx context.Context, url string) (io.ReadCloser, error) {
+	fetcher.ctx = ctx
+	return fetcher.Fetch(url)
+}
+
+func TestHTTPFetcher_FetchContext(t *testing.T) {
+	g := NewWithT(t)
+	release := make(chan struct{})
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer testServer.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := HTTPFetcher{}.FetchContext(ctx, testServer.URL)
+	g.Expect(err).Should(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring(context.DeadlineExceeded.Error()))
+}
+
+func TestCli_reader_context(t *testing.T) {
+	g := NewWithT(t)
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+	fetcher := &testContextFetcher{
+		testFetcher: func(url string) (io.ReadCloser, error) {
+			return ioutil.NopCloser(strings.NewReader("test")), nil
+		},
+	}
+	reader, err := New(map[string]interface{}{
+		optionFilePath: "https://registry.example.com/schema.yaml",
+	}, WithFetcher(fetcher), WithContext(ctx)).reader()
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(reader.Close()).To(Succeed())
+	g.Expect(fetcher.ctx).To(Equal(ctx))
+}
+
+func TestCli_reader_tls(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("test"))
+	}))
+	defer testServer.Close()
+
+	tests := []struct {
+		name      string
+		opts      map[string]interface{}
+		shouldErr bool
+	}{
+		{
+			name: "unknown certificate",
+			opts: map[string]interface{}{
+				optionFilePath: testServer.URL,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "insecure",
+			opts: map[string]interface{}{
+				optionFilePath: testServer.URL,
+				optionInsecure: true,
+			},
+		},
+		{
+			name: "missing ca certificate",
+			opts: map[string]interface{}{
+				optionFilePath: testServer.URL,
+				optionCACert:   "/invalid/path/to/a/file",
+			},
+			shouldErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			reader, err := New(test.opts).reader()
+			if test.shouldErr {
+				g.Expect(err).Should(HaveOccurred())
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(reader.Close()).To(Succeed())
+		})
+	}
+}
+
+func TestCli_newFetcher_error(t *testing.T) {
+	tests := []struct {
+		name string
+		opts map[string]interface{}
+	}{
+		{
+			name: "invalid header",
+			opts: map[string]interface{}{optionHeader: []string{"no colon"}},
+		},
+		{
+			name: "invalid timeout",
+			opts: map[string]interface{}{optionTimeout: "soon"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := New(test.opts).newFetcher()
+			g.Expect(err).Should(HaveOccurred())
+		})
+	}
+}
@@ -0,0 +1,405 @@
+package server
+
+import (
+	"github.com/pkg/errors"
+
+	v2 "github.com/asyncapi/converter-go/pkg/converter/v2"
+	"github.com/asyncapi/converter-go/pkg/decode"
+	"github.com/asyncapi/converter-go/pkg/encode"
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
+
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMaxBytes is the default maximum size of a converted document.
+	DefaultMaxBytes int64 = 10 << 20
+	// DefaultMaxDepth is the default maximum nesting depth of a converted document.
+	DefaultMaxDepth = 128
+	// DefaultMaxAliasExpansions is the default maximum number of YAML nodes decoded through the aliases.
+	DefaultMaxAliasExpansions = 10000
+	// DefaultTimeout is the default maximum duration of a single conversion request.
+	DefaultTimeout = 30 * time.Second
+
+	convertPath = "/convert"
+
+	headerWarning = "X-Conversion-Warning"
+
+	mediaTypeJSON = "application/json"
+	mediaTypeYAML = "application/x-yaml"
+
+	// statusClientClosedRequest is the non-standard status of the requests canceled by the client,
+	// so the disconnected clients are not reported as server errors.
+	statusClientClosedRequest = 499
+)
+
+var errUnknownParameter = errors.New("unknown query parameter")
+
+// queryOptions maps the query parameters of the convert request to converter options.
+// A nil option leaves the converter defaults.
+var queryOptions = map[string]func(string) (v2.ConverterOption, error){
+	"id": func(value string) (v2.ConverterOption, error) {
+		return v2.WithID(&value), nil
+	},
+	"serverNaming": func(value string) (v2.ConverterOption, error) {
+		namer, ok := v2.ServerNamerByName(value)
+		if !ok {
+			return nil, errors.Errorf("unknown server naming strategy: %s", value)
+		}
+		return v2.WithServerNamer(namer), nil
+	},
+	"serverEnvironments": func(value string) (v2.ConverterOption, error) {
+		environments, ok := v2.ServerEnvironmentsByName(value)
+		if !ok {
+			return nil, errors.Errorf("unknown server environments strategy: %s", value)
+		}
+		return v2.WithServerEnvironments(environments), nil
+	},
+	"channelNaming": func(value string) (v2.ConverterOption, error) {
+		mapper, ok := v2.ChannelNameMapperByName(value)
+		if !ok {
+			return nil, errors.Errorf("unknown channel naming strategy: %s", value)
+		}
+		return v2.WithChannelNameMapper(mapper), nil
+	},
+	"operationIds": func(value string) (v2.ConverterOption, error) {
+		namer, ok := v2.OperationIDNamerByName(value)
+		if !ok {
+			return nil, errors.Errorf("unknown operation id naming scheme: %s", value)
+		}
+		return v2.WithOperationIDs(namer), nil
+	},
+	"bindings": func(value string) (v2.ConverterOption, error) {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil || !enabled {
+			return nil, err
+		}
+		return v2.WithProtocolBindings(nil), nil
+	},
+	"liftComponents": func(value string) (v2.ConverterOption, error) {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil || !enabled {
+			return nil, err
+		}
+		return v2.WithComponentLifting(), nil
+	},
+	"dereference": func(value string) (v2.ConverterOption, error) {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil || !enabled {
+			return nil, err
+		}
+		return v2.WithDereferencing(), nil
+	},
+	"streamChannel": func(value string) (v2.ConverterOption, error) {
+		return v2.WithStreamChannelNamer(v2.ParseStreamChannelNamer(value)), nil
+	},
+	"streamDiscriminator": func(value string) (v2.ConverterOption, error) {
+		return v2.WithStreamDiscriminator(value), nil
+	},
+}
+
+type server struct {
+	maxBytes           int64
+	maxDepth           int
+	maxAliasExpansions int
+	timeout            time.Duration
+}
+
+// Option is a functional option that allows you to configure the conversion server.
+type Option func(*server) error
+
+// WithMaxBytes is a functional option that allows you to specify the maximum size of a converted document.
+func WithMaxBytes(maxBytes int64) Option {
+	return func(server *server) error {
+		if maxBytes <= 0 {
+			return errors.Errorf("invalid max bytes: %d", maxBytes)
+		}
+		server.maxBytes = maxBytes
+		return nil
+	}
+}
+
+// WithMaxDepth is a functional option that allows you to specify the maximum nesting depth of a converted document.
+func WithMaxDepth(maxDepth int) Option {
+	return func(server *server) error {
+		if maxDepth <= 0 {
+			return errors.Errorf("invalid max depth: %d", maxDepth)
+		}
+		server.maxDepth = maxDepth
+		return nil
+	}
+}
+
+// WithMaxAliasExpansions is a functional option that allows you to specify the maximum number of YAML nodes
+// decoded through the aliases of a converted document.
+func WithMaxAliasExpansions(maxAliasExpansions int) Option {
+	return func(server *server) error {
+		if maxAliasExpansions <= 0 {
+			return errors.Errorf("invalid max alias expansions: %d", maxAliasExpansions)
+		}
+		server.maxAliasExpansions = maxAliasExpansions
+		return nil
+	}
+}
+
+// WithTimeout is a functional option that allows you to specify the maximum duration of a conversion request.
+func WithTimeout(timeout time.Duration) Option {
+	return func(server *server) error {
+		if timeout <= 0 {
+			return errors.Errorf("invalid timeout: %s", timeout)
+		}
+		server.timeout = timeout
+		return nil
+	}
+}
+
+// New creates a new http.Handler that converts AsyncAPI documents sent in the body
+// of the POST /convert requests.
+//
+// The input format is detected from the Content-Type header or from the content itself,
+// the output format is negotiated with the Accept header. Query parameters are mapped
+// to the converter options, for example ?id=urn:example.
+func New(options ...Option) (http.Handler, error) {
+	server, err := newServer(options...)
+	if err != nil {
+		return nil, err
+	}
+	return server.handler(), nil
+}
+
+// NewHTTPServer creates an http.Server that serves the handler created by New on the TCP network
+// address addr. Its read and write timeouts are derived from the timeout of a conversion request.
+func NewHTTPServer(addr string, options ...Option) (*http.Server, error) {
+	server, err := newServer(options...)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Server{
+		Addr:              addr,
+		Handler:           server.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       server.timeout,
+		WriteTimeout:      server.timeout + 5*time.Second,
+	}, nil
+}
+
+// ListenAndServe listens on the address of the httpServer and serves its handler until ctx is done.
+// Then it shuts the server down gracefully, waiting for the active conversion requests to complete.
+func ListenAndServe(ctx context.Context, httpServer *http.Server) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.ListenAndServe()
+	}()
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), httpServer.WriteTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errs; err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func newServer(options ...Option) (server, error) {
+	server := server{
+		maxBytes:           DefaultMaxBytes,
+		maxDepth:           DefaultMaxDepth,
+		maxAliasExpansions: DefaultMaxAliasExpansions,
+		timeout:            DefaultTimeout,
+	}
+	for _, option := range options {
+		if err := option(&server); err != nil {
+			return server, err
+		}
+	}
+	return server, nil
+}
+
+// handler limits the duration of the requests with the context instead of http.TimeoutHandler, which responds
+// with 503 Service Unavailable also to the requests canceled by the clients. The conversion stops with the error
+// of the context, see statusCode. The time of reading the requests is limited by the HTTP server, see NewHTTPServer.
+func (s server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(convertPath, s.convert)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), s.timeout)
+		defer cancel()
+		mux.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (s server) convert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+
+	decodeFn, err := s.decoder(r.Header.Get("Content-Type"))
+	if err != nil {
+		writeError(w, http.StatusUnsupportedMediaType, err)
+		return
+	}
+
+	encodeFn, contentType, err := encoder(r.Header.Get("Accept"))
+	if err != nil {
+		writeError(w, http.StatusNotAcceptable, err)
+		return
+	}
+
+	options, err := converterOptions(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var warnings []v2.Warning
+	options = append(options,
+		v2.WithDereferenceLimits(v2.DereferenceLimits{MaxBytes: s.maxBytes, MaxDepth: s.maxDepth}),
+		v2.WithWarningHandler(func(warning v2.Warning) {
+			warnings = append(warnings, warning)
+		}),
+	)
+	converter, err := v2.New(decodeFn, encodeFn, options...)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, s.maxBytes+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if int64(len(body)) > s.maxBytes {
+		writeError(w, http.StatusRequestEntityTooLarge, errors.Errorf("document exceeds %d bytes", s.maxBytes))
+		return
+	}
+
+	var out bytes.Buffer
+	if err := converter.(v2.ContextConverter).ConvertContext(r.Context(), bytes.NewReader(body), &out); err != nil {
+		writeError(w, statusCode(err), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	for _, warning := range warnings {
+		w.Header().Add(headerWarning, warning.String())
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = out.WriteTo(w)
+}
+
+func (s server) decoder(contentType string) (v2.Decode, error) {
+	limits := decode.Limits{
+		MaxDepth:           s.maxDepth,
+		MaxAliasExpansions: s.maxAliasExpansions,
+	}
+	if contentType == "" {
+		return (&decode.Auto{Limits: limits}).Decode, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case isJSON(mediaType):
+		return limits.FromJSON, nil
+	case isYAML(mediaType):
+		return limits.FromYaml, nil
+	default:
+		return (&decode.Auto{Limits: limits}).Decode, nil
+	}
+}
+
+func encoder(accept string) (v2.Encode, string, error) {
+	if accept == "" {
+		return encode.ToJSON, mediaTypeJSON, nil
+	}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch {
+		case isJSON(mediaType), mediaType == "*/*", mediaType == "application/*":
+			return encode.ToJSON, mediaTypeJSON, nil
+		case isYAML(mediaType):
+			return encode.ToYaml, mediaTypeYAML, nil
+		}
+	}
+	return nil, "", errors.Errorf("unsupported accept header: %s", accept)
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == mediaTypeJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+func isYAML(mediaType string) bool {
+	switch mediaType {
+	case "application/yaml", mediaTypeYAML, "text/yaml", "text/x-yaml":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+yaml")
+}
+
+func converterOptions(r *http.Request) ([]v2.ConverterOption, error) {
+	var options []v2.ConverterOption
+	for name, values := range r.URL.Query() {
+		newOption, ok := queryOptions[name]
+		if !ok {
+			return nil, errors.Wrap(errUnknownParameter, name)
+		}
+		option, err := newOption(values[len(values)-1])
+		if err != nil {
+			return nil, errors.Wrap(err, name)
+		}
+		if option != nil {
+			options = append(options, option)
+		}
+	}
+	return options, nil
+}
+
+// statusCode returns the HTTP status code matching the kind of the conversion error.
+func statusCode(err error) int {
+	switch {
+	case asyncapierr.IsInvalidDocument(err):
+		return http.StatusBadRequest
+	case asyncapierr.IsInvalidProperty(err), asyncapierr.IsUnsupportedAsyncapiVersion(err):
+		return http.StatusUnprocessableEntity
+	case asyncapierr.IsDocumentVersionUpToDate(err):
+		return http.StatusConflict
+	case asyncapierr.IsLimitExceeded(err):
+		return http.StatusRequestEntityTooLarge
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return http.StatusServiceUnavailable
+	case stderrors.Is(err, context.Canceled):
+		return statusClientClosedRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintln(w, err.Error())
+}
@@ -0,0 +1,455 @@
+package server
+
+import (
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testDocument = `{
+    "asyncapi": "1.2.0",
+    "info": {
+        "title": "Test",
+        "version": "1.0.0"
+    },
+    "topics": {
+        "test": {
+            "publish": {
+                "payload": {
+                    "type": "string"
+                }
+            }
+        }
+    }
+}`
+
+const testDocumentYAML = `asyncapi: 1.2.0
+info:
+  title: Test
+  version: 1.0.0
+topics:
+  test:
+    publish:
+      payload:
+        type: string
+`
+
+// expandingDocument returns a document whose schemas reference the next schema twice,
+// so the dereferenced document doubles with each of the levels.
+func expandingDocument(levels int) string {
+	var schemas []string
+	for i := 0; i < levels; i++ {
+		ref := fmt.Sprintf(`{"$ref": "#/components/schemas/s%d"}`, i+1)
+		schemas = append(schemas, fmt.Sprintf(`"s%d": {"type": "array", "items": [%s, %s]}`, i, ref, ref))
+	}
+	schemas = append(schemas, fmt.Sprintf(`"s%d": {"type": "string"}`, levels))
+	return `{
+    "asyncapi": "1.2.0",
+    "info": {"title": "Test", "version": "1.0.0"},
+    "topics": {"test": {"publish": {"payload": {"$ref": "#/components/schemas/s0"}}}},
+    "components": {"schemas": {` + strings.Join(schemas, ", ") + `}}
+}`
+}
+
+func TestServer_convert(t *testing.T) {
+	tests := []struct {
+		name                string
+		method              string
+		target              string
+		body                string
+		contentType         string
+		accept              string
+		options             []Option
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+		expectedWarnings    []string
+	}{
+		{
+			name:                "json to json",
+			body:                testDocument,
+			contentType:         "application/json",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: mediaTypeJSON,
+			expectedBody:        `"asyncapi":"2.0.0"`,
+		},
+		{
+			name:                "yaml detected from content to yaml",
+			body:                testDocumentYAML,
+			accept:              "application/yaml",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: mediaTypeYAML,
+			expectedBody:        "asyncapi: 2.0.0",
+		},
+		{
+			name:                "yaml content type with wildcard accept",
+			body:                testDocumentYAML,
+			contentType:         "application/x-yaml",
+			accept:              "text/html, */*;q=0.8",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: mediaTypeJSON,
+			expectedBody:        `"channels":{"test"`,
+		},
+		{
+			name:                "id query parameter",
+			target:              "/convert?id=urn:test",
+			body:                testDocument,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: mediaTypeJSON,
+			expectedBody:        `"id":"urn:test"`,
+		},
+		{
+			name:                "server naming query parameter",
+			target:              "/convert?serverNaming=host",
+			body:                `{"asyncapi": "1.2.0", "servers": [{"url": "api.example.com", "scheme": "mqtt"}], "topics": {}}`,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: mediaTypeJSON,
+			expectedBody:        `"servers":{"api.example.com"`,
+		},
+		{
+			name:           "invalid server naming query parameter",
+			target:         "/convert?serverNaming=unknown",
+			body:           testDocument,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:                "server environments query parameter",
+			target:              "/convert?serverEnvironments=merge",
+			body:                `{"asyncapi": "1.2.0", "servers": [{"url": "dev.example.com", "scheme": "mqtt"}, {"url": "prod.example.com", "scheme": "mqtt"}], "topics": {}}`,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: mediaTypeJSON,
+			expectedBody:        `"url":"{environment}.example.com"`,
+		},
+		{
+			name:                "channel naming query parameter",
+			target:              "/convert?channelNaming=kafka",
+			body:                `{"asyncapi": "1.2.0", "baseTopic": "user", "topics": {"events.v1": {"publish": {}}}}`,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: mediaTypeJSON,
+			expectedBody:        `"channels":{"user.events.v1"`,
+		},
+		{
+			name:                "bindings query parameter",
+			target:              "/convert?bindings=true",
+			body:                `{"asyncapi": "1.2.0", "topics": {"test": {"x-ws-method": "GET"}}}`,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: mediaTypeJSON,
+			expectedBody:        `"bindings":{"ws":{"method":"GET"}}`,
+		},
+		{
+			name:                "disabled bindings query parameter",
+			target:              "/convert?bindings=false",
+			body:                `{"asyncapi": "1.2.0", "topics": {"test": {"x-ws-method": "GET"}}}`,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: mediaTypeJSON,
+			expectedBody:        `"x-ws-method":"GET"`,
+		},
+		{
+			name:           "invalid bindings query parameter",
+			target:         "/convert?bindings=maybe",
+			body:           testDocument,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:                "stream channel query parameter",
+			target:              "/convert?streamChannel=server-path",
+			body:                `{"asyncapi": "1.2.0", "servers": [{"url": "wss://example.com/ws", "scheme": "wss"}], "stream": {}}`,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: mediaTypeJSON,
+			expectedBody:        `"channels":{"/ws":{}}`,
+		},
+		{
+			name:                "lift components query parameter",
+			target:              "/convert?liftComponents=true",
+			body:                testDocument,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: mediaTypeJSON,
+			expectedBody:        `"message":{"$ref":"#/components/messages/testPublish"}`,
+		},
+		{
+			name:                "dereference query parameter",
+			target:              "/convert?dereference=true&liftComponents=true",
+			body:                testDocument,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: mediaTypeJSON,
+			expectedBody:        `"message":{"payload":{"type":"string"}}`,
+		},
+		{
+			name:                "operation ids query parameter",
+			target:              "/convert?operationIds=snake",
+			body:                testDocument,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: mediaTypeJSON,
+			expectedBody:        `"operationId":"publish_test"`,
+		},
+		{
+			name:                "conversion warnings",
+			body:                `{"asyncapi": "1.2.0", "servers": [{"url": "api.example.com", "scheme": "mqtt"}], "security": [{"apiKey": []}], "topics": {}}`,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: mediaTypeJSON,
+			expectedWarnings:    []string{"/security/0/apiKey: dropped reference to undefined security scheme"},
+		},
+		{
+			name:           "unknown query parameter",
+			target:         "/convert?unknown=1",
+			body:           testDocument,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid method",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "not acceptable",
+			body:           testDocument,
+			accept:         "text/html",
+			expectedStatus: http.StatusNotAcceptable,
+		},
+		{
+			name:           "invalid document",
+			body:           "[]",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid property",
+			body:           `{"asyncapi": "1.2.0"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "unsupported version",
+			body:           `{"asyncapi": "0.1.0"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "document up to date",
+			body:           `{"asyncapi": "2.0.0"}`,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "document too large",
+			body:           testDocument,
+			options:        []Option{WithMaxBytes(10)},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "document too deep",
+			body:           testDocument,
+			contentType:    "application/json",
+			options:        []Option{WithMaxDepth(2)},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "asyncapi: document exceeds the maximum depth of 2",
+		},
+		{
+			name:           "too many alias expansions",
+			body:           "a: &a [x, x, x]\nb: [*a, *a]\n",
+			contentType:    "application/x-yaml",
+			options:        []Option{WithMaxAliasExpansions(4)},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "asyncapi: document exceeds the maximum alias expansion of 4",
+		},
+		{
+			name:           "dereferenced document too large",
+			target:         "/convert?dereference=true",
+			body:           expandingDocument(20),
+			options:        []Option{WithMaxBytes(64 << 10)},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "asyncapi: document exceeds the maximum dereferenced size in bytes of 65536",
+		},
+		{
+			name:           "dereferenced document too deep",
+			target:         "/convert?dereference=true",
+			body:           expandingDocument(20),
+			options:        []Option{WithMaxDepth(16)},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "asyncapi: document exceeds the maximum dereferenced depth of 16",
+		},
+		{
+			name:           "timeout",
+			body:           testDocument,
+			options:        []Option{WithTimeout(time.Nanosecond)},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "context deadline exceeded",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			handler, err := New(test.options...)
+			g.Expect(err).ShouldNot(HaveOccurred())
+
+			method := test.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			target := test.target
+			if target == "" {
+				target = convertPath
+			}
+			request := httptest.NewRequest(method, target, strings.NewReader(test.body))
+			if test.contentType != "" {
+				request.Header.Set("Content-Type", test.contentType)
+			}
+			if test.accept != "" {
+				request.Header.Set("Accept", test.accept)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			response := recorder.Result()
+			body, err := ioutil.ReadAll(response.Body)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(response.StatusCode).To(Equal(test.expectedStatus), string(body))
+			if test.expectedContentType != "" {
+				g.Expect(response.Header.Get("Content-Type")).To(Equal(test.expectedContentType))
+			}
+			g.Expect(string(body)).To(ContainSubstring(test.expectedBody))
+			g.Expect(response.Header[headerWarning]).To(Equal(test.expectedWarnings))
+		})
+	}
+}
+
+func TestServer_convert_canceled(t *testing.T) {
+	g := NewWithT(t)
+	handler, err := New()
+	g.Expect(err).ShouldNot(HaveOccurred())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request := httptest.NewRequest(http.MethodPost, convertPath, strings.NewReader(testDocument)).WithContext(ctx)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	g.Expect(recorder.Code).To(Equal(statusClientClosedRequest))
+}
+
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{err: context.DeadlineExceeded, expected: http.StatusServiceUnavailable},
+		{err: fmt.Errorf("fetch: %w", context.DeadlineExceeded), expected: http.StatusServiceUnavailable},
+		{err: context.Canceled, expected: statusClientClosedRequest},
+		{err: fmt.Errorf("fetch: %w", context.Canceled), expected: statusClientClosedRequest},
+		{err: asyncapierr.NewLimitExceeded("depth", 1), expected: http.StatusRequestEntityTooLarge},
+		{err: io.ErrUnexpectedEOF, expected: http.StatusInternalServerError},
+	}
+	for _, test := range tests {
+		t.Run(test.err.Error(), func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(statusCode(test.err)).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestServer_httptest(t *testing.T) {
+	g := NewWithT(t)
+	handler, err := New()
+	g.Expect(err).ShouldNot(HaveOccurred())
+	testServer := httptest.NewServer(handler)
+	defer testServer.Close()
+
+	response, err := http.Post(testServer.URL+convertPath, "application/json", strings.NewReader(testDocument))
+	g.Expect(err).ShouldNot(HaveOccurred())
+	defer response.Body.Close()
+	g.Expect(response.StatusCode).To(Equal(http.StatusOK))
+}
+
+func TestNew_invalid_options(t *testing.T) {
+	tests := []struct {
+		name   string
+		option Option
+	}{
+		{
+			name:   "max bytes",
+			option: WithMaxBytes(0),
+		},
+		{
+			name:   "max depth",
+			option: WithMaxDepth(0),
+		},
+		{
+			name:   "max alias expansions",
+			option: WithMaxAliasExpansions(-1),
+		},
+		{
+			name:   "timeout",
+			option: WithTimeout(-time.Second),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := New(test.option)
+			g.Expect(err).Should(HaveOccurred())
+		})
+	}
+}
+
+func TestNewHTTPServer(t *testing.T) {
+	tests := []struct {
+		name                 string
+		options              []Option
+		expectedReadTimeout  time.Duration
+		expectedWriteTimeout time.Duration
+	}{
+		{
+			name:                 "default timeout",
+			expectedReadTimeout:  DefaultTimeout,
+			expectedWriteTimeout: DefaultTimeout + 5*time.Second,
+		},
+		{
+			name:                 "timeout longer than default",
+			options:              []Option{WithTimeout(2 * time.Minute)},
+			expectedReadTimeout:  2 * time.Minute,
+			expectedWriteTimeout: 2*time.Minute + 5*time.Second,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			httpServer, err := NewHTTPServer("127.0.0.1:0", test.options...)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(httpServer.Addr).To(Equal("127.0.0.1:0"))
+			g.Expect(httpServer.Handler).ShouldNot(BeNil())
+			g.Expect(httpServer.ReadTimeout).To(Equal(test.expectedReadTimeout))
+			g.Expect(httpServer.WriteTimeout).To(Equal(test.expectedWriteTimeout))
+		})
+	}
+}
+
+func TestNewHTTPServer_invalid_options(t *testing.T) {
+	g := NewWithT(t)
+	_, err := NewHTTPServer("127.0.0.1:0", WithTimeout(0))
+	g.Expect(err).Should(HaveOccurred())
+}
+
+func TestListenAndServe(t *testing.T) {
+	g := NewWithT(t)
+	httpServer, err := NewHTTPServer("127.0.0.1:0")
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- ListenAndServe(ctx, httpServer)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	g.Eventually(errs, time.Second).Should(Receive(BeNil()))
+}
+
+func TestListenAndServe_error(t *testing.T) {
+	g := NewWithT(t)
+	httpServer, err := NewHTTPServer("invalid address")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(ListenAndServe(context.Background(), httpServer)).Should(HaveOccurred())
+}
@@ -0,0 +1,314 @@
+package watch
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/asyncapi/converter-go/pkg/decode"
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
+
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultInterval is the default interval of polling the watched files for changes.
+	DefaultInterval = 500 * time.Millisecond
+	// DefaultDebounce is the default quiet period after the last change of a file
+	// before the file is converted. It coalesces rapid saves into a single conversion.
+	DefaultDebounce = 300 * time.Millisecond
+)
+
+var (
+	errNoPaths     = errors.New("no paths to watch")
+	errOutDirEmpty = errors.New("output directory not specified")
+
+	yamlLineRegexp = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+)
+
+// Converter converts an AsyncAPI document.
+type Converter interface {
+	Convert(reader io.Reader, writer io.Writer) error
+}
+
+// NewConverter creates a converter of the document stored under the path.
+// It returns the converter and the extension of the output file, for example .json.
+type NewConverter = func(path string) (Converter, string, error)
+
+type fileState struct {
+	modTime   time.Time
+	size      int64
+	changedAt time.Time
+	pending   bool
+	// collided is set if the output file of the document was written from another document.
+	collided bool
+}
+
+// Watcher polls AsyncAPI documents for changes and converts them into the output directory.
+// The output file is named after the document, so documents with the same name, such as a/api.yaml
+// and b/api.yaml or api.yaml and api.json, would overwrite each other's output. Only the first of them,
+// in the lexical order of the paths, is converted and the others are reported as errors.
+type Watcher struct {
+	paths        []string
+	outDir       string
+	interval     time.Duration
+	debounce     time.Duration
+	newConverter NewConverter
+	log          io.Writer
+	now          func() time.Time
+	files        map[string]*fileState
+	// outputs maps the written output files to the watched files they were converted from.
+	outputs map[string]string
+}
+
+// Option is a functional option that allows you to configure the Watcher.
+type Option func(*Watcher) error
+
+// WithInterval is a functional option that allows you to specify the interval of polling the files for changes.
+func WithInterval(interval time.Duration) Option {
+	return func(watcher *Watcher) error {
+		if interval <= 0 {
+			return errors.Errorf("invalid interval: %s", interval)
+		}
+		watcher.interval = interval
+		return nil
+	}
+}
+
+// WithDebounce is a functional option that allows you to specify the quiet period after the last change
+// of a file before the file is converted.
+func WithDebounce(debounce time.Duration) Option {
+	return func(watcher *Watcher) error {
+		if debounce < 0 {
+			return errors.Errorf("invalid debounce: %s", debounce)
+		}
+		watcher.debounce = debounce
+		return nil
+	}
+}
+
+// WithLog is a functional option that allows you to specify where the conversion results and errors are printed.
+func WithLog(log io.Writer) Option {
+	return func(watcher *Watcher) error {
+		watcher.log = log
+		return nil
+	}
+}
+
+// New creates a new Watcher of the paths. Directories are watched for the json and yaml files they contain.
+func New(paths []string, outDir string, newConverter NewConverter, options ...Option) (*Watcher, error) {
+	if len(paths) == 0 {
+		return nil, errNoPaths
+	}
+	if outDir == "" {
+		return nil, errOutDirEmpty
+	}
+	watcher := Watcher{
+		paths:        paths,
+		outDir:       outDir,
+		interval:     DefaultInterval,
+		debounce:     DefaultDebounce,
+		newConverter: newConverter,
+		log:          os.Stderr,
+		now:          time.Now,
+		files:        make(map[string]*fileState),
+		outputs:      make(map[string]string),
+	}
+	for _, option := range options {
+		if err := option(&watcher); err != nil {
+			return nil, err
+		}
+	}
+	return &watcher, nil
+}
+
+// Run converts all watched documents and then reconverts them whenever they change, until stop is closed.
+// Conversion errors are printed and do not stop the Watcher.
+func (w *Watcher) Run(stop <-chan struct{}) error {
+	if err := os.MkdirAll(w.outDir, 0755); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.poll()
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Watcher) poll() {
+	now := w.now()
+	files := w.watchedFiles()
+	w.releaseOutputs(files)
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		state, ok := w.files[path]
+		if !ok {
+			state = &fileState{pending: true}
+			w.files[path] = state
+		}
+		if !ok || !info.ModTime().Equal(state.modTime) || info.Size() != state.size {
+			state.modTime = info.ModTime()
+			state.size = info.Size()
+			state.changedAt = now
+			state.pending = true
+		}
+		if state.pending && now.Sub(state.changedAt) >= w.debounce {
+			state.pending = false
+			w.convert(path)
+		}
+	}
+}
+
+func (w *Watcher) watchedFiles() []string {
+	var files []string
+	for _, path := range w.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			w.logf("%s: %s", path, err)
+			continue
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			w.logf("%s: %s", path, err)
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && isDocument(entry.Name()) {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+func isDocument(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+func (w *Watcher) convert(path string) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		w.logf("%s: %s", path, err)
+		return
+	}
+	converter, ext, err := w.newConverter(path)
+	if err != nil {
+		w.logf("%s: %s", path, err)
+		return
+	}
+	name := filepath.Base(path)
+	outPath := filepath.Join(w.outDir, strings.TrimSuffix(name, filepath.Ext(name))+ext)
+	if source, ok := w.outputs[outPath]; ok && source != path {
+		w.logf("%s: output %s collides with the output of %s", path, outPath, source)
+		w.files[path].collided = true
+		return
+	}
+	w.outputs[outPath] = path
+	var out bytes.Buffer
+	if err := converter.Convert(bytes.NewReader(data), &out); err != nil {
+		w.logf("%s", describeError(path, data, err))
+		return
+	}
+	if err := ioutil.WriteFile(outPath, out.Bytes(), 0644); err != nil {
+		w.logf("%s: %s", outPath, err)
+		return
+	}
+	w.logf("%s: converted to %s", path, outPath)
+}
+
+// releaseOutputs forgets the output files of the documents that are no longer watched,
+// and converts again the documents whose output collided with them.
+func (w *Watcher) releaseOutputs(files []string) {
+	watched := make(map[string]bool, len(files))
+	for _, path := range files {
+		watched[path] = true
+	}
+	released := false
+	for outPath, source := range w.outputs {
+		if !watched[source] {
+			delete(w.outputs, outPath)
+			released = true
+		}
+	}
+	if !released {
+		return
+	}
+	for _, state := range w.files {
+		if state.collided {
+			state.collided = false
+			state.pending = true
+		}
+	}
+}
+
+func (w *Watcher) logf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(w.log, format+"\n", args...)
+}
+
+// describeError formats the conversion error with the position in the document, if it is known.
+// The converter reports every syntax error as an invalid document, so the document is decoded
+// again to find the position of the syntax error.
+func describeError(path string, data []byte, err error) string {
+	if asyncapierr.IsInvalidDocument(err) {
+		var v interface{}
+		decodeFn := decode.FromYaml
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			decodeFn = decode.FromJSON
+		}
+		if syntaxErr := decodeFn(&v, bytes.NewReader(data)); syntaxErr != nil {
+			err = syntaxErr
+		}
+	}
+	switch err := err.(type) {
+	case *json.SyntaxError:
+		line, column := position(data, err.Offset)
+		return fmt.Sprintf("%s:%d:%d: %s", path, line, column, err)
+	case *json.UnmarshalTypeError:
+		line, column := position(data, err.Offset)
+		return fmt.Sprintf("%s:%d:%d: %s", path, line, column, err)
+	}
+	if matches := yamlLineRegexp.FindStringSubmatch(err.Error()); matches != nil {
+		return fmt.Sprintf("%s:%s: %s", path, matches[1], matches[2])
+	}
+	return fmt.Sprintf("%s: %s", path, err)
+}
+
+// position returns the line and column of the byte reported by the JSON decoder, which sets
+// the offset of an error to the number of bytes read, including the offending byte.
+func position(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	if offset > 0 {
+		offset--
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
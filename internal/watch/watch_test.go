@@ -0,0 +1,239 @@
+package watch
+
+import (
+	. "github.com/onsi/gomega"
+
+	v2 "github.com/asyncapi/converter-go/pkg/converter/v2"
+	"github.com/asyncapi/converter-go/pkg/decode"
+	"github.com/asyncapi/converter-go/pkg/encode"
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
+
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testDocument = `asyncapi: 1.2.0
+info:
+  title: Test
+  version: 1.0.0
+topics:
+  test:
+    publish:
+      payload:
+        type: string
+`
+
+type syncBuffer struct {
+	sync.Mutex
+	bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.Buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	return b.Buffer.String()
+}
+
+func newTestConverter(_ string) (Converter, string, error) {
+	converter, err := v2.New(decode.FromJSONWithYamlFallback, encode.ToJSON)
+	return converter, ".json", err
+}
+
+func TestWatcher_Run(t *testing.T) {
+	g := NewWithT(t)
+	dir, err := ioutil.TempDir("", "asyncapi-converter-watch")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	inputPath := filepath.Join(dir, "test.yaml")
+	outputPath := filepath.Join(dir, "out", "test.json")
+	g.Expect(ioutil.WriteFile(inputPath, []byte(testDocument), 0644)).To(Succeed())
+
+	var log syncBuffer
+	watcher, err := New([]string{dir}, filepath.Join(dir, "out"), newTestConverter,
+		WithInterval(10*time.Millisecond),
+		WithDebounce(30*time.Millisecond),
+		WithLog(&log),
+	)
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- watcher.Run(stop)
+	}()
+
+	readOutput := func() string {
+		data, _ := ioutil.ReadFile(outputPath)
+		return string(data)
+	}
+	g.Eventually(readOutput, time.Second).Should(ContainSubstring(`"asyncapi":"2.0.0"`))
+
+	g.Expect(ioutil.WriteFile(inputPath, []byte("asyncapi: 1.2.0\ntopics: [\n"), 0644)).To(Succeed())
+	g.Eventually(log.String, time.Second).Should(ContainSubstring(inputPath + ":2:"))
+
+	g.Expect(ioutil.WriteFile(inputPath, []byte(testDocument+"id: urn:test\n"), 0644)).To(Succeed())
+	g.Eventually(readOutput, time.Second).Should(ContainSubstring(`"id":"urn:test"`))
+
+	close(stop)
+	g.Eventually(done, time.Second).Should(Receive(BeNil()))
+}
+
+func TestWatcher_poll_debounce(t *testing.T) {
+	g := NewWithT(t)
+	dir, err := ioutil.TempDir("", "asyncapi-converter-watch")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	inputPath := filepath.Join(dir, "test.yaml")
+	g.Expect(ioutil.WriteFile(inputPath, []byte(testDocument), 0644)).To(Succeed())
+
+	conversions := 0
+	newConverter := func(path string) (Converter, string, error) {
+		conversions++
+		return newTestConverter(path)
+	}
+	var log bytes.Buffer
+	watcher, err := New([]string{inputPath}, dir, newConverter, WithDebounce(time.Minute), WithLog(&log))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	now := time.Now()
+	watcher.now = func() time.Time {
+		return now
+	}
+	for i := 0; i < 3; i++ {
+		g.Expect(ioutil.WriteFile(inputPath, []byte(testDocument+"\n\n"[:i]), 0644)).To(Succeed())
+		watcher.poll()
+		now = now.Add(time.Second)
+	}
+	g.Expect(conversions).To(Equal(0))
+
+	now = now.Add(time.Minute)
+	watcher.poll()
+	watcher.poll()
+	g.Expect(conversions).To(Equal(1))
+}
+
+func TestWatcher_poll_output_collision(t *testing.T) {
+	g := NewWithT(t)
+	dir, err := ioutil.TempDir("", "asyncapi-converter-watch")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"a", "b"} {
+		g.Expect(os.Mkdir(filepath.Join(dir, name), 0755)).To(Succeed())
+		g.Expect(ioutil.WriteFile(filepath.Join(dir, name, "api.yaml"), []byte(testDocument+"id: urn:"+name+"\n"), 0644)).To(Succeed())
+	}
+	g.Expect(ioutil.WriteFile(filepath.Join(dir, "b", "api.json"), []byte(`{"asyncapi": "1.2.0", "id": "urn:json", "topics": {}}`), 0644)).To(Succeed())
+
+	outDir := filepath.Join(dir, "out")
+	g.Expect(os.Mkdir(outDir, 0755)).To(Succeed())
+	var log bytes.Buffer
+	watcher, err := New([]string{filepath.Join(dir, "b"), filepath.Join(dir, "a", "api.yaml")}, outDir, newTestConverter,
+		WithDebounce(0), WithLog(&log))
+	g.Expect(err).ShouldNot(HaveOccurred())
+	watcher.poll()
+
+	outPath := filepath.Join(outDir, "api.json")
+	data, err := ioutil.ReadFile(outPath)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(string(data)).To(ContainSubstring(`"id":"urn:a"`))
+	for _, name := range []string{"api.json", "api.yaml"} {
+		g.Expect(log.String()).To(ContainSubstring(
+			filepath.Join(dir, "b", name) + ": output " + outPath + " collides with the output of " + filepath.Join(dir, "a", "api.yaml")))
+	}
+
+	g.Expect(os.Remove(filepath.Join(dir, "a", "api.yaml"))).To(Succeed())
+	watcher.paths = watcher.paths[:1]
+	watcher.poll()
+	data, err = ioutil.ReadFile(outPath)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(string(data)).To(ContainSubstring(`"id":"urn:json"`))
+}
+
+func TestNew_error(t *testing.T) {
+	tests := []struct {
+		name    string
+		paths   []string
+		outDir  string
+		options []Option
+	}{
+		{
+			name:   "no paths",
+			outDir: "out",
+		},
+		{
+			name:  "no output directory",
+			paths: []string{"test.yaml"},
+		},
+		{
+			name:    "invalid interval",
+			paths:   []string{"test.yaml"},
+			outDir:  "out",
+			options: []Option{WithInterval(0)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := New(test.paths, test.outDir, newTestConverter, test.options...)
+			g.Expect(err).Should(HaveOccurred())
+		})
+	}
+}
+
+func TestDescribeError(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		data     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "json syntax error",
+			path:     "test.json",
+			data:     "{\n  \"asyncapi\": x\n}",
+			err:      asyncapierr.NewInvalidDocument(),
+			expected: "test.json:2:15: invalid character 'x' looking for beginning of value",
+		},
+		{
+			name:     "json syntax error at the beginning of a line",
+			path:     "test.json",
+			data:     "{\n  \"asyncapi\": \"1.2.0\"\nx}",
+			err:      asyncapierr.NewInvalidDocument(),
+			expected: "test.json:3:1: invalid character 'x' after object key:value pair",
+		},
+		{
+			name:     "yaml syntax error",
+			path:     "test.yaml",
+			data:     "asyncapi: 1.2.0\ntopics: [\n",
+			err:      asyncapierr.NewInvalidDocument(),
+			expected: "test.yaml:2: did not find expected node content",
+		},
+		{
+			name:     "conversion error",
+			path:     "test.yaml",
+			data:     "asyncapi: 1.2.0",
+			err:      asyncapierr.NewInvalidProperty("topics"),
+			expected: "test.yaml: asyncapi: error invalid property topics",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(describeError(test.path, []byte(test.data), test.err)).To(Equal(test.expected))
+		})
+	}
+}
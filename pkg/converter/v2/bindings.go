@@ -0,0 +1,266 @@
+package v2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultBindingPrefixes maps the 1.x extension prefixes to the protocols of the generated bindings.
+var DefaultBindingPrefixes = map[string]string{
+	"x-kafka-": "kafka",
+	"x-amqp-":  "amqp",
+	"x-mqtt-":  "mqtt",
+	"x-ws-":    "ws",
+	"x-http-":  "http",
+}
+
+// operationBindingFields are the binding fields of the operations. As 1.x defines the publish and subscribe
+// messages without the operations, these fields are moved from the bindings of the messages to the operations.
+var operationBindingFields = map[string]map[string]bool{
+	"kafka": {"groupId": true, "clientId": true},
+	"amqp": {
+		"expiration": true, "userId": true, "cc": true, "priority": true, "deliveryMode": true,
+		"mandatory": true, "bcc": true, "replyTo": true, "timestamp": true, "ack": true,
+	},
+	"mqtt": {"qos": true, "retain": true},
+	"http": {"type": true, "method": true, "query": true},
+}
+
+// amqpProtocols are the server protocols of the documents whose channel names are AMQP routing keys.
+// The routing key is the only binding derived from the servers: the 1.x servers describe no other field
+// of the Kafka, MQTT, WebSocket or HTTP bindings, so these are generated from the extensions only.
+var amqpProtocols = map[string]bool{
+	"amqp":  true,
+	"amqps": true,
+}
+
+// WithProtocolBindings is a functional option that enables the generation of the bindings of servers, channels,
+// operations and messages from the 1.x extensions. The prefixes map the extension prefixes to the protocols,
+// for example x-kafka-group-id of an operation results in the bindings.kafka.groupId property of the operation.
+// An extension named after the prefix without the trailing dash, for example x-kafka, holds the whole binding.
+// The operation fields of the message bindings, such as kafka groupId, are moved to the operations, including
+// the fields of the component messages, which are moved to the operations referring to the messages.
+// If prefixes is nil, DefaultBindingPrefixes is used.
+//
+// Besides the extensions, the channels of documents that define only AMQP servers are bound as routing keys.
+// The bindings of the other protocols are not derived from the servers.
+func WithProtocolBindings(prefixes map[string]string) ConverterOption {
+	return func(converter *converter) error {
+		if prefixes == nil {
+			prefixes = DefaultBindingPrefixes
+		}
+		if err := ValidateBindingPrefixes(prefixes); err != nil {
+			return err
+		}
+		converter.bindingPrefixes = prefixes
+		return nil
+	}
+}
+
+// ValidateBindingPrefixes returns an error if one of the prefixes is not an extension prefix starting with x-
+// or it is not mapped to a protocol.
+func ValidateBindingPrefixes(prefixes map[string]string) error {
+	for _, prefix := range sortedPrefixes(prefixes) {
+		if !strings.HasPrefix(prefix, "x-") || prefixes[prefix] == "" {
+			return fmt.Errorf("invalid binding prefix: %s", prefix)
+		}
+	}
+	return nil
+}
+
+// updateBindings moves the protocol extensions into the bindings. It runs after updateServers and alterChannels,
+// so it works on the 2.0.0 servers, channels, operations and messages.
+func (c *converter) updateBindings() error {
+	if c.bindingPrefixes == nil {
+		return nil
+	}
+	servers, _ := c.data["servers"].(map[string]interface{})
+	for _, server := range servers {
+		c.extensionsToBindings(server)
+	}
+
+	routingKeys := len(servers) > 0
+	for _, item := range servers {
+		server, _ := item.(map[string]interface{})
+		if protocol, _ := server["protocol"].(string); !amqpProtocols[protocol] {
+			routingKeys = false
+		}
+	}
+
+	componentBindings := c.componentMessageBindings()
+	referenced := make(map[string]bool)
+	channels, _ := c.data["channels"].(map[string]interface{})
+	for _, item := range channels {
+		if err := c.checkContext(); err != nil {
+			return err
+		}
+		channel, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		c.extensionsToBindings(channel)
+		if routingKeys {
+			setDefaultBinding(channel, "amqp", "is", "routingKey")
+		}
+		for _, name := range topicOperations {
+			operation, ok := channel[name].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			c.extensionsToBindings(operation)
+			forEachMessage(operation["message"], func(message map[string]interface{}) {
+				c.extensionsToBindings(message)
+				moveOperationBindings(message, operation)
+				if ref, ok := message["$ref"].(string); ok && componentBindings[ref] != nil {
+					mergeBindings(componentBindings[ref], operation)
+					referenced[ref] = true
+				}
+			})
+		}
+	}
+
+	for _, ref := range sortedKeys(componentBindings) {
+		if !referenced[ref] {
+			c.warn(ref[1:], "dropped operation bindings, because no operation refers to the message")
+		}
+	}
+	return nil
+}
+
+// componentMessageBindings moves the extensions of the component messages into their bindings, and returns
+// the operation binding fields removed from them by the references to the messages, such as #/components/messages/hello.
+// The fields are moved to the operations referring to the messages.
+func (c *converter) componentMessageBindings() map[string]interface{} {
+	components, _ := c.data["components"].(map[string]interface{})
+	messages, _ := components["messages"].(map[string]interface{})
+	result := make(map[string]interface{})
+	for name, item := range messages {
+		message, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		c.extensionsToBindings(message)
+		operation := make(map[string]interface{})
+		moveOperationBindings(message, operation)
+		if len(operation) > 0 {
+			result["#/components/messages/"+escapePointer(name)] = operation
+		}
+	}
+	return result
+}
+
+// mergeBindings adds the bindings of the source to the object, the fields already set in the object take precedence.
+func mergeBindings(source interface{}, object map[string]interface{}) {
+	bindings, _ := source.(map[string]interface{})["bindings"].(map[string]interface{})
+	for protocol, item := range bindings {
+		fields, _ := item.(map[string]interface{})
+		for field, value := range fields {
+			setDefaultBinding(object, protocol, field, value)
+		}
+	}
+}
+
+// extensionsToBindings moves the extensions matching the binding prefixes of the item into its bindings.
+func (c *converter) extensionsToBindings(item interface{}) {
+	object, ok := item.(map[string]interface{})
+	if !ok {
+		return
+	}
+	prefixes := sortedPrefixes(c.bindingPrefixes)
+	for _, key := range sortedKeys(object) {
+		value := object[key]
+		for _, prefix := range prefixes {
+			protocol := c.bindingPrefixes[prefix]
+			if strings.HasPrefix(key, prefix) && len(key) > len(prefix) {
+				setBinding(object, protocol, bindingField(key[len(prefix):]), value)
+				delete(object, key)
+				break
+			}
+			if fields, ok := value.(map[string]interface{}); ok && key == strings.TrimSuffix(prefix, "-") {
+				for field, fieldValue := range fields {
+					setBinding(object, protocol, field, fieldValue)
+				}
+				delete(object, key)
+				break
+			}
+		}
+	}
+}
+
+// sortedPrefixes returns the binding prefixes from the longest one, so the most specific prefix matches first.
+func sortedPrefixes(prefixes map[string]string) []string {
+	keys := make([]string, 0, len(prefixes))
+	for prefix := range prefixes {
+		keys = append(keys, prefix)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// moveOperationBindings moves the operation binding fields from the bindings of the message to the operation.
+func moveOperationBindings(message, operation map[string]interface{}) {
+	bindings, ok := message["bindings"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for protocol, item := range bindings {
+		protocolBinding, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for field, value := range protocolBinding {
+			if operationBindingFields[protocol][field] {
+				setBinding(operation, protocol, field, value)
+				delete(protocolBinding, field)
+			}
+		}
+		if len(protocolBinding) == 0 {
+			delete(bindings, protocol)
+		}
+	}
+	if len(bindings) == 0 {
+		delete(message, "bindings")
+	}
+}
+
+func binding(object map[string]interface{}, protocol string) map[string]interface{} {
+	bindings, ok := object["bindings"].(map[string]interface{})
+	if !ok {
+		bindings = make(map[string]interface{})
+		object["bindings"] = bindings
+	}
+	protocolBinding, ok := bindings[protocol].(map[string]interface{})
+	if !ok {
+		protocolBinding = make(map[string]interface{})
+		bindings[protocol] = protocolBinding
+	}
+	return protocolBinding
+}
+
+func setBinding(object map[string]interface{}, protocol, field string, value interface{}) {
+	binding(object, protocol)[field] = value
+}
+
+func setDefaultBinding(object map[string]interface{}, protocol, field string, value interface{}) {
+	protocolBinding := binding(object, protocol)
+	if _, ok := protocolBinding[field]; !ok {
+		protocolBinding[field] = value
+	}
+}
+
+// bindingField converts the kebab-case extension suffix into the camelCase binding field, for example group-id into groupId.
+func bindingField(suffix string) string {
+	parts := strings.Split(suffix, "-")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,246 @@
+package v2
+
+import (
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestUpdateBindings(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefixes map[string]string
+		servers  []interface{}
+		topic    map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name: "disabled",
+			topic: map[string]interface{}{
+				"x-kafka-partitions": 3,
+			},
+			expected: map[string]interface{}{
+				"x-kafka-partitions": 3,
+			},
+		},
+		{
+			name:     "channel and operations",
+			prefixes: DefaultBindingPrefixes,
+			topic: map[string]interface{}{
+				"x-ws-method": "GET",
+				"publish": map[string]interface{}{
+					"x-kafka-key":         map[string]interface{}{"type": "string"},
+					"x-mqtt-qos":          1,
+					"x-http-method":       "POST",
+					"x-amqp-message-type": "created",
+				},
+			},
+			expected: map[string]interface{}{
+				"bindings": map[string]interface{}{
+					"ws": map[string]interface{}{"method": "GET"},
+				},
+				"publish": map[string]interface{}{
+					"bindings": map[string]interface{}{
+						"mqtt": map[string]interface{}{"qos": 1},
+						"http": map[string]interface{}{"method": "POST"},
+					},
+					"message": map[string]interface{}{
+						"bindings": map[string]interface{}{
+							"kafka": map[string]interface{}{"key": map[string]interface{}{"type": "string"}},
+							"amqp":  map[string]interface{}{"messageType": "created"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "overlapping prefixes",
+			prefixes: map[string]string{"x-kafka-": "kafka", "x-kafka-confluent-": "confluent"},
+			topic: map[string]interface{}{
+				"x-kafka-confluent-schema-id": 7,
+				"x-kafka-cluster":             "main",
+			},
+			expected: map[string]interface{}{
+				"bindings": map[string]interface{}{
+					"kafka":     map[string]interface{}{"cluster": "main"},
+					"confluent": map[string]interface{}{"schemaId": 7},
+				},
+			},
+		},
+		{
+			name:     "whole binding extension",
+			prefixes: DefaultBindingPrefixes,
+			topic: map[string]interface{}{
+				"x-amqp": map[string]interface{}{"is": "queue", "queue": map[string]interface{}{"name": "orders"}},
+			},
+			expected: map[string]interface{}{
+				"bindings": map[string]interface{}{
+					"amqp": map[string]interface{}{"is": "queue", "queue": map[string]interface{}{"name": "orders"}},
+				},
+			},
+		},
+		{
+			name:     "custom prefixes",
+			prefixes: map[string]string{"x-confluent-": "kafka"},
+			topic: map[string]interface{}{
+				"x-confluent-cleanup-policy": "compact",
+				"x-kafka-partitions":         3,
+			},
+			expected: map[string]interface{}{
+				"bindings": map[string]interface{}{
+					"kafka": map[string]interface{}{"cleanupPolicy": "compact"},
+				},
+				"x-kafka-partitions": 3,
+			},
+		},
+		{
+			name:     "amqp routing keys",
+			prefixes: DefaultBindingPrefixes,
+			servers: []interface{}{
+				map[string]interface{}{"url": "broker.example.com", "scheme": "amqp"},
+				map[string]interface{}{"url": "broker.example.com", "scheme": "amqps", "x-amqp-vhost": "/"},
+			},
+			topic: map[string]interface{}{
+				"x-amqp-exchange": map[string]interface{}{"name": "events"},
+			},
+			expected: map[string]interface{}{
+				"bindings": map[string]interface{}{
+					"amqp": map[string]interface{}{"is": "routingKey", "exchange": map[string]interface{}{"name": "events"}},
+				},
+			},
+		},
+		{
+			name:     "no bindings derived from other protocols",
+			prefixes: DefaultBindingPrefixes,
+			servers: []interface{}{
+				map[string]interface{}{"url": "broker.example.com", "scheme": "kafka"},
+				map[string]interface{}{"url": "broker.example.com", "scheme": "mqtt"},
+				map[string]interface{}{"url": "api.example.com", "scheme": "ws"},
+			},
+			topic:    map[string]interface{}{},
+			expected: map[string]interface{}{},
+		},
+		{
+			name:     "mixed protocols",
+			prefixes: DefaultBindingPrefixes,
+			servers: []interface{}{
+				map[string]interface{}{"url": "broker.example.com", "scheme": "amqp"},
+				map[string]interface{}{"url": "broker.example.com", "scheme": "mqtt"},
+			},
+			topic:    map[string]interface{}{},
+			expected: map[string]interface{}{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := converter{
+				data: map[string]interface{}{
+					"topics": map[string]interface{}{
+						"test": test.topic,
+					},
+				},
+			}
+			if test.servers != nil {
+				c.data["servers"] = test.servers
+			}
+			if test.prefixes != nil {
+				g.Expect(WithProtocolBindings(test.prefixes)(&c)).To(Succeed())
+			}
+			g.Expect(c.updateServers()).To(Succeed())
+			g.Expect(c.createChannels()).To(Succeed())
+			g.Expect(c.alterChannels()).To(Succeed())
+			g.Expect(c.updateBindings()).To(Succeed())
+			g.Expect(c.data["channels"]).To(HaveKeyWithValue("test", test.expected))
+		})
+	}
+}
+
+func TestUpdateBindings_servers_and_components(t *testing.T) {
+	g := NewWithT(t)
+	c := converter{
+		data: map[string]interface{}{
+			"servers": []interface{}{
+				map[string]interface{}{"url": "broker.example.com", "scheme": "mqtt", "x-mqtt-keep-alive": 60},
+			},
+			"components": map[string]interface{}{
+				"messages": map[string]interface{}{
+					"userSignedUp": map[string]interface{}{"x-kafka-key": map[string]interface{}{"type": "string"}},
+				},
+			},
+		},
+	}
+	g.Expect(WithProtocolBindings(nil)(&c)).To(Succeed())
+	g.Expect(c.updateServers()).To(Succeed())
+	g.Expect(c.updateBindings()).To(Succeed())
+	g.Expect(c.data["servers"]).To(HaveKeyWithValue("default", HaveKeyWithValue("bindings", map[string]interface{}{
+		"mqtt": map[string]interface{}{"keepAlive": 60},
+	})))
+	g.Expect(c.data["components"]).To(HaveKeyWithValue("messages", HaveKeyWithValue("userSignedUp", map[string]interface{}{
+		"bindings": map[string]interface{}{
+			"kafka": map[string]interface{}{"key": map[string]interface{}{"type": "string"}},
+		},
+	})))
+}
+
+func TestUpdateBindings_component_messages(t *testing.T) {
+	g := NewWithT(t)
+	var warnings []string
+	c := converter{
+		warningHandler: func(warning Warning) {
+			warnings = append(warnings, warning.String())
+		},
+		data: map[string]interface{}{
+			"channels": map[string]interface{}{
+				"a": map[string]interface{}{
+					"publish": map[string]interface{}{
+						"message":           map[string]interface{}{"$ref": "#/components/messages/created"},
+						"x-kafka-client-id": "own",
+					},
+				},
+			},
+			"components": map[string]interface{}{
+				"messages": map[string]interface{}{
+					"created": map[string]interface{}{
+						"x-kafka-group-id":  "consumers",
+						"x-kafka-client-id": "shared",
+						"x-kafka-key":       map[string]interface{}{"type": "string"},
+					},
+					"unused": map[string]interface{}{"x-mqtt-qos": 1},
+				},
+			},
+		},
+	}
+	g.Expect(WithProtocolBindings(nil)(&c)).To(Succeed())
+	g.Expect(c.updateBindings()).To(Succeed())
+	g.Expect(c.data["channels"]).To(HaveKeyWithValue("a", HaveKeyWithValue("publish", map[string]interface{}{
+		"message": map[string]interface{}{"$ref": "#/components/messages/created"},
+		"bindings": map[string]interface{}{
+			"kafka": map[string]interface{}{"groupId": "consumers", "clientId": "own"},
+		},
+	})))
+	g.Expect(c.data["components"]).To(HaveKeyWithValue("messages", map[string]interface{}{
+		"created": map[string]interface{}{
+			"bindings": map[string]interface{}{
+				"kafka": map[string]interface{}{"key": map[string]interface{}{"type": "string"}},
+			},
+		},
+		"unused": map[string]interface{}{},
+	}))
+	g.Expect(warnings).To(Equal([]string{
+		"/components/messages/unused: dropped operation bindings, because no operation refers to the message",
+	}))
+}
+
+func TestWithProtocolBindings_invalid(t *testing.T) {
+	g := NewWithT(t)
+	_, err := New(nil, nil, WithProtocolBindings(map[string]string{"kafka-": "kafka"}))
+	g.Expect(err).Should(HaveOccurred())
+}
+
+func TestValidateBindingPrefixes(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(ValidateBindingPrefixes(DefaultBindingPrefixes)).To(Succeed())
+	g.Expect(ValidateBindingPrefixes(map[string]string{"x-kafka-": "kafka", "kafka-": "kafka"})).To(MatchError("invalid binding prefix: kafka-"))
+	g.Expect(ValidateBindingPrefixes(map[string]string{"x-kafka-": ""})).To(MatchError("invalid binding prefix: x-kafka-"))
+}
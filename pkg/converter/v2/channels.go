@@ -0,0 +1,105 @@
+package v2
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	channelParameterRegexp = regexp.MustCompile(`{[^}]+}`)
+	nonKafkaCharRegexp     = regexp.MustCompile(`[^A-Za-z0-9._\-]+`)
+)
+
+// ChannelNameMapper returns the name of the channel converted from the 1.x topic.
+// The baseTopic is empty if the document does not define it.
+type ChannelNameMapper func(baseTopic, topic string) (string, error)
+
+// WithChannelNameMapper is a functional option that allows you to specify how the topics are mapped
+// to the channel names. By default, the converter uses SlashPathChannelNames.
+func WithChannelNameMapper(mapper ChannelNameMapper) ConverterOption {
+	return func(converter *converter) error {
+		converter.channelNameMapper = mapper
+		return nil
+	}
+}
+
+// SlashPathChannelNames joins the baseTopic and the topic with / and replaces all dots with /,
+// for example smartylighting.streetlights.1.0 and event.lighting.measured result in
+// smartylighting/streetlights/1/0/event/lighting/measured.
+func SlashPathChannelNames(baseTopic, topic string) (string, error) {
+	return strings.ReplaceAll(joinTopic(baseTopic, topic, "/"), ".", "/"), nil
+}
+
+// KeepDotsChannelNames joins the baseTopic and the topic with a dot the way AsyncAPI 1.x does,
+// for example user and events.v1 result in user.events.v1.
+func KeepDotsChannelNames(baseTopic, topic string) (string, error) {
+	return joinTopic(baseTopic, topic, "."), nil
+}
+
+// KafkaChannelNames joins the baseTopic and the topic with a dot, replaces slashes with dots
+// and the characters that are not allowed in Kafka topic names with underscores.
+// The channel parameters, for example {userId}, are kept.
+func KafkaChannelNames(baseTopic, topic string) (string, error) {
+	name := strings.ReplaceAll(joinTopic(baseTopic, topic, "."), "/", ".")
+	var result strings.Builder
+	last := 0
+	for _, match := range channelParameterRegexp.FindAllStringIndex(name, -1) {
+		result.WriteString(nonKafkaCharRegexp.ReplaceAllString(name[last:match[0]], "_"))
+		result.WriteString(name[match[0]:match[1]])
+		last = match[1]
+	}
+	result.WriteString(nonKafkaCharRegexp.ReplaceAllString(name[last:], "_"))
+	return result.String(), nil
+}
+
+// AMQPRoutingKeyChannelNames joins the baseTopic and the topic with a dot and replaces slashes with dots,
+// so the channel name is an AMQP routing key made of dot-separated words.
+func AMQPRoutingKeyChannelNames(baseTopic, topic string) (string, error) {
+	return strings.ReplaceAll(joinTopic(baseTopic, topic, "."), "/", "."), nil
+}
+
+// MQTTWildcardChannelNames maps the topics like SlashPathChannelNames does and replaces
+// the channel parameters with the MQTT single-level wildcard, for example
+// event.{streetlightId}.measured results in event/+/measured. The replaced parameters are dropped from the channel
+// and reported as warnings, as the 2.0.0 channel parameters describe the parameters of the channel name only.
+func MQTTWildcardChannelNames(baseTopic, topic string) (string, error) {
+	name, err := SlashPathChannelNames(baseTopic, topic)
+	if err != nil {
+		return "", err
+	}
+	return channelParameterRegexp.ReplaceAllString(name, "+"), nil
+}
+
+// ChannelNameMapperByName returns the built-in ChannelNameMapper with the name:
+// slash-path, keep-dots, kafka, amqp or mqtt.
+func ChannelNameMapperByName(name string) (ChannelNameMapper, bool) {
+	switch name {
+	case "slash-path":
+		return SlashPathChannelNames, true
+	case "keep-dots":
+		return KeepDotsChannelNames, true
+	case "kafka":
+		return KafkaChannelNames, true
+	case "amqp":
+		return AMQPRoutingKeyChannelNames, true
+	case "mqtt":
+		return MQTTWildcardChannelNames, true
+	default:
+		return nil, false
+	}
+}
+
+func joinTopic(baseTopic, topic, separator string) string {
+	if baseTopic == "" {
+		return topic
+	}
+	return baseTopic + separator + topic
+}
+
+func (c *converter) channelName(baseTopic, topic string) (string, error) {
+	mapper := c.channelNameMapper
+	if mapper == nil {
+		mapper = SlashPathChannelNames
+	}
+	return mapper(baseTopic, topic)
+}
@@ -0,0 +1,145 @@
+package v2
+
+import (
+	. "github.com/onsi/gomega"
+
+	"errors"
+	"testing"
+)
+
+func TestChannelNameMappers(t *testing.T) {
+	tests := []struct {
+		name      string
+		mapper    ChannelNameMapper
+		baseTopic string
+		topic     string
+		expected  string
+	}{
+		{"slash-path", SlashPathChannelNames, "smartylighting.streetlights.1.0", "event.{streetlightId}.measured", "smartylighting/streetlights/1/0/event/{streetlightId}/measured"},
+		{"slash-path without base topic", SlashPathChannelNames, "", "user.events.v1", "user/events/v1"},
+		{"keep-dots", KeepDotsChannelNames, "user", "events.v1", "user.events.v1"},
+		{"keep-dots without base topic", KeepDotsChannelNames, "", "user.events.v1", "user.events.v1"},
+		{"kafka", KafkaChannelNames, "user", "events/v1 created#{userId}", "user.events.v1_created_{userId}"},
+		{"amqp", AMQPRoutingKeyChannelNames, "orders", "eu/{region}.created", "orders.eu.{region}.created"},
+		{"mqtt", MQTTWildcardChannelNames, "smartylighting", "event.{streetlightId}.{sensor}", "smartylighting/event/+/+"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			name, err := test.mapper(test.baseTopic, test.topic)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(name).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestChannelsFromTopics_mapper(t *testing.T) {
+	tests := []struct {
+		name             string
+		mapper           ChannelNameMapper
+		expected         map[string]interface{}
+		expectedWarnings []string
+		shouldErr        bool
+	}{
+		{
+			name: "default",
+			expected: map[string]interface{}{
+				"user/events/v1/{userId}": HaveKey("parameters"),
+				"user/events/v2":          HaveKey("publish"),
+			},
+		},
+		{
+			name:   "kafka",
+			mapper: KafkaChannelNames,
+			expected: map[string]interface{}{
+				"user.events.v1.{userId}": HaveKey("parameters"),
+				"user.events.v2":          HaveKey("publish"),
+			},
+		},
+		{
+			name:   "mqtt drops parameters",
+			mapper: MQTTWildcardChannelNames,
+			expected: map[string]interface{}{
+				"user/events/v1/+": And(HaveKey("subscribe"), Not(HaveKey("parameters"))),
+				"user/events/v2":   HaveKey("publish"),
+			},
+			expectedWarnings: []string{
+				"/channels/user~1events~1v1~1+/parameters/userId: dropped parameter, because the channel name does not refer to it",
+			},
+		},
+		{
+			name: "custom",
+			mapper: func(baseTopic, topic string) (string, error) {
+				return "/" + topic, nil
+			},
+			expected: map[string]interface{}{
+				"/events.v1.{userId}": HaveKey("parameters"),
+				"/events/v2":          HaveKey("publish"),
+			},
+		},
+		{
+			name: "collision",
+			mapper: func(baseTopic, topic string) (string, error) {
+				return baseTopic, nil
+			},
+			shouldErr: true,
+		},
+		{
+			name: "error",
+			mapper: func(baseTopic, topic string) (string, error) {
+				return "", errors.New("test error")
+			},
+			shouldErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var warnings []string
+			c := converter{
+				channelNameMapper: test.mapper,
+				warningHandler: func(warning Warning) {
+					warnings = append(warnings, warning.String())
+				},
+				data: map[string]interface{}{
+					"baseTopic": "user",
+					"topics": map[string]interface{}{
+						"events.v1.{userId}": map[string]interface{}{
+							"parameters": []interface{}{
+								map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+							},
+							"subscribe": map[string]interface{}{},
+						},
+						"events/v2": map[string]interface{}{
+							"publish": map[string]interface{}{},
+						},
+					},
+				},
+			}
+			err := c.createChannels()
+			if test.shouldErr {
+				g.Expect(err).Should(HaveOccurred())
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(c.alterChannels()).To(Succeed())
+			channels := c.data["channels"].(map[string]interface{})
+			g.Expect(channels).To(HaveLen(len(test.expected)))
+			for name, matcher := range test.expected {
+				g.Expect(channels).To(HaveKeyWithValue(name, matcher))
+			}
+			g.Expect(warnings).To(Equal(test.expectedWarnings))
+		})
+	}
+}
+
+func TestChannelNameMapperByName(t *testing.T) {
+	g := NewWithT(t)
+	for _, name := range []string{"slash-path", "keep-dots", "kafka", "amqp", "mqtt"} {
+		mapper, ok := ChannelNameMapperByName(name)
+		g.Expect(ok).To(BeTrue(), name)
+		g.Expect(mapper).ShouldNot(BeNil(), name)
+	}
+	_, ok := ChannelNameMapperByName("unknown")
+	g.Expect(ok).To(BeFalse())
+}
@@ -0,0 +1,41 @@
+package v2
+
+import (
+	"context"
+	"io"
+)
+
+// contextReader stops reading with the error of the context once the context is done,
+// so a slow reader, for example the body of an HTTP response, does not outlive the conversion.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
+
+// contextWriter stops writing with the error of the context once the context is done.
+type contextWriter struct {
+	ctx    context.Context
+	writer io.Writer
+}
+
+func (w contextWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return w.writer.Write(p)
+}
+
+// checkContext returns the error of the context of the conversion, if the context is done.
+func (c *converter) checkContext() error {
+	if c.ctx == nil {
+		return nil
+	}
+	return c.ctx.Err()
+}
@@ -1,6 +1,7 @@
 package v2
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"regexp"
@@ -25,11 +26,37 @@ type Converter interface {
 	Convert(reader io.Reader, writer io.Writer) error
 }
 
+// ContextConverter is a Converter that can be stopped with a context. The converters created by New implement it:
+//
+//	converter, err := v2.New(decode.FromJSON, encode.ToJSON)
+//	err = converter.(v2.ContextConverter).ConvertContext(ctx, reader, writer)
+type ContextConverter interface {
+	Converter
+	// ConvertContext works like Convert, but stops with the error of the context, such as context.Canceled,
+	// as soon as the context is done. The context is checked while reading and writing the document,
+	// between the conversion steps and in the loops over the channels, operations and components.
+	ConvertContext(ctx context.Context, reader io.Reader, writer io.Writer) error
+}
+
+var _ ContextConverter = &converter{}
+
 type converter struct {
-	id     *string
-	data   map[string]interface{}
-	decode Decode
-	encode Encode
+	id                  *string
+	serverNamer         ServerNamer
+	serverEnvironments  ServerEnvironments
+	channelNameMapper   ChannelNameMapper
+	bindingPrefixes     map[string]string
+	streamChannelNamer  StreamChannelNamer
+	streamDiscriminator string
+	liftComponents      bool
+	dereference         bool
+	dereferenceLimits   *DereferenceLimits
+	operationIDNamer    OperationIDNamer
+	warningHandler      WarningHandler
+	data                map[string]interface{}
+	decode              Decode
+	encode              Encode
+	ctx                 context.Context
 }
 
 func (c *converter) buildEncodeFunction(writer io.Writer) func() error {
@@ -41,30 +68,61 @@ func (c *converter) buildEncodeFunction(writer io.Writer) func() error {
 func (c *converter) buildDecodeFunction(reader io.Reader) func() error {
 	return func() error {
 		var data interface{}
-		decode := c.decode(&data, reader)
+		if err := c.decode(&data, reader); err != nil {
+			if ctxErr := c.checkContext(); ctxErr != nil {
+				return ctxErr
+			}
+			if asyncapierr.IsLimitExceeded(err) {
+				return err
+			}
+			return asyncapierr.NewInvalidDocumentWithCause(err)
+		}
 		var ok bool
 		c.data, ok = data.(map[string]interface{})
 		if !ok {
 			return asyncapierr.NewInvalidDocument()
 		}
-		return decode
+		return nil
 	}
 }
 
 func (c *converter) Convert(reader io.Reader, writer io.Writer) error {
+	return c.ConvertContext(context.Background(), reader, writer)
+}
+
+func (c *converter) ConvertContext(ctx context.Context, reader io.Reader, writer io.Writer) error {
+	c.ctx = ctx
+	defer func() {
+		c.ctx = nil
+	}()
+	if reader != nil {
+		reader = contextReader{ctx: ctx, reader: reader}
+	}
+	if writer != nil {
+		writer = contextWriter{ctx: ctx, writer: writer}
+	}
 	steps := []func() error{
 		c.buildDecodeFunction(reader),
 		c.verifyAsyncapiVersion,
 		c.updateID,
 		c.updateVersion,
+		c.updateSecuritySchemes,
+		c.updateSecurity,
 		c.updateServers,
 		c.createChannels,
 		c.alterChannels,
+		c.updateBindings,
+		c.updateOperationIDs,
 		c.updateComponents,
+		c.liftInlineComponents,
 		c.cleanup,
+		c.dereferenceComponents,
 		c.buildEncodeFunction(writer),
 	}
 	for _, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		err := step()
 		if err != nil {
 			return err
@@ -120,7 +178,7 @@ func (c *converter) updateServers() error {
 		return nil
 	}
 
-	_, containsSecurity := c.data["security"]
+	security, _ := c.data["security"].([]interface{})
 	for _, item := range servers {
 		server, ok := item.(map[string]interface{})
 		if !ok {
@@ -128,8 +186,8 @@ func (c *converter) updateServers() error {
 		}
 		server["protocol"] = server["scheme"]
 		delete(server, "scheme")
-		if containsSecurity {
-			server["security"] = c.data["security"]
+		if _, ok := server["security"]; !ok && len(security) > 0 {
+			server["security"] = c.serverSecurity()
 		}
 		if schemaVersion, ok := server["schemeVersion"]; ok {
 			server["protocolVersion"] = schemaVersion
@@ -137,110 +195,14 @@ func (c *converter) updateServers() error {
 		}
 	}
 
-	var mappedServers = make(map[string]interface{})
-	for index, item := range servers {
-		//done same way as in https://github.com/asyncapi/converter/blob/020946e745342a6751565406e156c499859f5763/lib/index.js#L106
-		if index == 0 {
-			mappedServers["default"] = item
-		} else {
-			mappedServers[fmt.Sprintf("server%d", index)] = item
-		}
+	mappedServers, err := c.nameServers(servers)
+	if err != nil {
+		return err
 	}
-
 	c.data["servers"] = mappedServers
 	return nil
 }
 
-func (c *converter) channelsFromTopics() error {
-	channels := make(map[string]interface{})
-	topics, ok := c.data["topics"].(map[string]interface{})
-	if !ok {
-		return asyncapierr.NewInvalidProperty("topics")
-	}
-	for key, value := range topics {
-		var topicName string
-		if _, ok := c.data["baseTopic"]; ok {
-			topicName = fmt.Sprintf("%v", c.data["baseTopic"])
-		}
-		if topicName != "" {
-			topicName = fmt.Sprintf(`%s/%s`, topicName, key)
-		} else {
-			topicName = fmt.Sprintf("%v", key)
-		}
-
-		channelKey := strings.ReplaceAll(topicName, ".", "/")
-
-		if topic, ok := value.(map[string]interface{}); ok {
-			switch {
-			case topic["publish"] != nil:
-				topic["publish"] = map[string]interface{}{
-					"message": topic["publish"],
-				}
-			case topic["subscribe"] != nil:
-				topic["subscribe"] = map[string]interface{}{
-					"message": topic["subscribe"],
-				}
-			}
-		}
-		channels[channelKey] = value
-	}
-	c.data["channels"] = channels
-	return nil
-}
-
-func fillChannelMessage(channel *map[string]interface{}, slice []interface{}, operation string) {
-	if len(slice) == 1 {
-		(*channel)[operation] = map[string]interface{}{
-			"message": slice[0],
-		}
-	} else {
-		(*channel)[operation] = map[string]map[string]interface{}{
-			"message": {
-				"oneOf": slice,
-			},
-		}
-	}
-}
-
-func (c *converter) channelsFromStream() error {
-	stream, ok := c.data["stream"].(map[string]interface{})
-	if !ok {
-		return asyncapierr.NewInvalidProperty("stream")
-	}
-	channel := make(map[string]interface{})
-
-	if streamRead, ok := stream["read"].([]interface{}); ok {
-		fillChannelMessage(&channel, streamRead, "subscribe")
-	}
-
-	if streamWrite, ok := stream["write"].([]interface{}); ok {
-		fillChannelMessage(&channel, streamWrite, "publish")
-	}
-	c.data["channels"] = map[string]interface{}{
-		"/": channel,
-	}
-	return nil
-}
-
-func (c *converter) channelsFromEvents() error {
-	events, ok := c.data["events"].(map[string]interface{})
-	if !ok {
-		return asyncapierr.NewInvalidProperty("events")
-	}
-	channel := make(map[string]interface{})
-	if eventsReceive, ok := events["receive"].([]interface{}); ok {
-		fillChannelMessage(&channel, eventsReceive, "subscribe")
-	}
-	if eventsSend, ok := events["send"].([]interface{}); ok {
-		fillChannelMessage(&channel, eventsSend, "publish")
-
-	}
-	c.data["channels"] = map[string]interface{}{
-		"/": channel,
-	}
-	return nil
-}
-
 func (c *converter) cleanup() error {
 	delete(c.data, "topics")
 	delete(c.data, "baseTopic")
@@ -278,7 +240,8 @@ func (c *converter) updateComponents() error {
 
 	for _, messageRaw := range messages {
 		if message, ok := messageRaw.(map[string]interface{}); ok {
-			headersToSchema(&message)
+			c.headersToSchema(message)
+			deprecateMessage(message)
 		}
 
 	}
@@ -333,11 +296,14 @@ func (c *converter) alterChannels() error {
 		return asyncapierr.NewInvalidProperty("missing channels")
 	}
 
-	for key, item := range channels {
-		channel, ok := item.(map[string]interface{})
+	for _, key := range sortedKeys(channels) {
+		channel, ok := channels[key].(map[string]interface{})
 		if !ok {
 			return asyncapierr.NewInvalidProperty("malformed channel")
 		}
+		if err := c.checkContext(); err != nil {
+			return err
+		}
 
 		if params, ok := channel["parameters"].([]interface{}); ok {
 			alteredParameters, err := alterParameters(params, key)
@@ -348,36 +314,44 @@ func (c *converter) alterChannels() error {
 		}
 
 		if publish, ok := channel["publish"].(map[string]interface{}); ok {
-			alterOperation(&publish)
+			c.alterOperation(publish)
 		}
 
 		if subscribe, ok := channel["subscribe"].(map[string]interface{}); ok {
-			alterOperation(&subscribe)
+			c.alterOperation(subscribe)
 		}
 	}
 	return nil
 }
 
-func headersToSchema(arg *map[string]interface{}) {
-	headers := (*arg)["headers"]
+// headersToSchema wraps the headers of the message into an object schema. The headers that are already wrapped
+// are kept, so the messages shared by several operations, for example by a decoder that does not copy
+// the YAML aliases, are wrapped once. The 1.x headers map the header names to the schemas, so their type key
+// never holds the object string.
+func (c *converter) headersToSchema(message map[string]interface{}) {
+	headers := message["headers"]
+	if isHeadersSchema(headers) {
+		return
+	}
 	if headers != nil {
-		(*arg)["headers"] = map[string]interface{}{
+		message["headers"] = map[string]interface{}{
 			"type":       "object",
 			"properties": headers,
 		}
 	}
 }
 
-func alterOperation(operation *map[string]interface{}) {
-	if message, ok := (*operation)["message"].(map[string]interface{}); ok {
-		if oneOf, ok := message["oneOf"].([]map[string]interface{}); ok {
-			for _, elem := range oneOf {
-				headersToSchema(&elem)
-			}
-		} else {
-			headersToSchema(&message)
-		}
+func isHeadersSchema(headers interface{}) bool {
+	schema, ok := headers.(map[string]interface{})
+	if !ok || schema["type"] != "object" {
+		return false
 	}
+	_, ok = schema["properties"].(map[string]interface{})
+	return ok
+}
+
+func (c *converter) alterOperation(operation map[string]interface{}) {
+	forEachMessage(operation["message"], c.headersToSchema)
 }
 
 func (c *converter) verifyAsyncapiVersion() error {
@@ -3,14 +3,19 @@ package v2
 import (
 	"github.com/asyncapi/converter-go/pkg/decode"
 	"github.com/asyncapi/converter-go/pkg/encode"
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
 	. "github.com/onsi/gomega"
 
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewJsonConverter(t *testing.T) {
@@ -59,6 +64,13 @@ func TestNewJsonConverter(t *testing.T) {
 			inputFilePath:    "./testdata/input/gitter-streaming1.2.0_more_servers.json",
 			expectedFilePath: "./testdata/output/gitter-streaming_more_servers.json",
 		},
+		{
+			inputFilePath:    "./testdata/input/gitter-streaming1.2.0.json",
+			expectedFilePath: "./testdata/output/gitter-streaming_server_path.json",
+			options: []ConverterOption{
+				WithStreamChannelNamer(StreamChannelFromServerPath),
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.inputFilePath, func(t *testing.T) {
@@ -175,6 +187,10 @@ func TestNewYamlConverter(t *testing.T) {
 			inputFilePath:    "./testdata/input/gitter-streaming1.2.0_one_read_stream.yaml",
 			expectedFilePath: "./testdata/output/gitter-streaming_one_read_stream.yaml",
 		},
+		{
+			inputFilePath:    "./testdata/input/streetlights1.2.0_publish_subscribe.yaml",
+			expectedFilePath: "./testdata/output/streetlights_publish_subscribe.yaml",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.inputFilePath, func(t *testing.T) {
@@ -306,3 +322,208 @@ func TestVerifyAsyncapiVersion_error(t *testing.T) {
 		})
 	}
 }
+
+func TestConvert_decodeError(t *testing.T) {
+	g := NewWithT(t)
+	input := &decode.Auto{}
+	converter, err := New(input.Decode, encode.ToJSON)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	err = converter.Convert(strings.NewReader(`{"asyncapi": [x}`), ioutil.Discard)
+	g.Expect(asyncapierr.IsInvalidDocument(err)).To(BeTrue())
+	g.Expect(err.Error()).To(Equal("asyncapi: unable to decode document: json: invalid character 'x' looking for beginning of value; yaml: did not find expected ',' or ']'"))
+}
+
+func TestConvert_decodeLimitExceeded(t *testing.T) {
+	g := NewWithT(t)
+	converter, err := New(decode.Limits{MaxDepth: 1}.FromJSON, encode.ToJSON)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	err = converter.Convert(strings.NewReader(`{"info": {}}`), ioutil.Discard)
+	g.Expect(asyncapierr.IsLimitExceeded(err)).To(BeTrue())
+}
+
+type cancelReader struct {
+	cancel context.CancelFunc
+	reader io.Reader
+}
+
+func (r cancelReader) Read(p []byte) (int, error) {
+	r.cancel()
+	return r.reader.Read(p)
+}
+
+func TestConvertContext(t *testing.T) {
+	document := `{"asyncapi": "1.2.0", "info": {"title": "test", "version": "1.0.0"}, "topics": {"test": {}}}`
+	tests := []struct {
+		name    string
+		decode  func(cancel context.CancelFunc) Decode
+		reader  func(cancel context.CancelFunc) io.Reader
+		wantErr error
+	}{
+		{
+			name: "done before conversion",
+			decode: func(cancel context.CancelFunc) Decode {
+				cancel()
+				return decode.FromJSON
+			},
+			reader: func(context.CancelFunc) io.Reader {
+				return strings.NewReader(document)
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			name: "done while reading",
+			decode: func(context.CancelFunc) Decode {
+				return decode.FromJSON
+			},
+			reader: func(cancel context.CancelFunc) io.Reader {
+				return cancelReader{cancel: cancel, reader: strings.NewReader(document)}
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			name: "done after decoding",
+			decode: func(cancel context.CancelFunc) Decode {
+				return func(v interface{}, reader io.Reader) error {
+					defer cancel()
+					return decode.FromJSON(v, reader)
+				}
+			},
+			reader: func(context.CancelFunc) io.Reader {
+				return strings.NewReader(document)
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			name: "not done",
+			decode: func(context.CancelFunc) Decode {
+				return decode.FromJSON
+			},
+			reader: func(context.CancelFunc) io.Reader {
+				return strings.NewReader(document)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			converter, err := New(test.decode(cancel), encode.ToJSON)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			var out bytes.Buffer
+			err = converter.(ContextConverter).ConvertContext(ctx, test.reader(cancel), &out)
+			if test.wantErr != nil {
+				g.Expect(err).To(Equal(test.wantErr))
+				g.Expect(out.Len()).To(BeZero())
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(out.String()).To(ContainSubstring(`"asyncapi":"2.0.0"`))
+		})
+	}
+}
+
+func TestConvertContext_deadline(t *testing.T) {
+	g := NewWithT(t)
+	ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	converter, err := New(decode.FromJSON, encode.ToJSON)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	err = converter.(ContextConverter).ConvertContext(ctx, strings.NewReader(`{"asyncapi": "1.2.0"}`), ioutil.Discard)
+	g.Expect(err).To(Equal(context.DeadlineExceeded))
+}
+
+func TestConvert_sharedMessages(t *testing.T) {
+	g := NewWithT(t)
+	decodeShared := func(v interface{}, _ io.Reader) error {
+		message := map[string]interface{}{
+			"headers": map[string]interface{}{"id": map[string]interface{}{"type": "string"}},
+		}
+		*v.(*interface{}) = map[string]interface{}{
+			"asyncapi": "1.2.0",
+			"info":     map[string]interface{}{"title": "shared", "version": "1.0.0"},
+			"topics": map[string]interface{}{
+				"a": map[string]interface{}{"publish": message, "subscribe": message},
+				"b": map[string]interface{}{"publish": message},
+			},
+			"components": map[string]interface{}{
+				"messages": map[string]interface{}{"shared": message},
+			},
+		}
+		return nil
+	}
+	converter, err := New(decodeShared, encode.ToJSON)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	var out bytes.Buffer
+	g.Expect(converter.Convert(strings.NewReader(""), &out)).To(Succeed())
+	headers := `"headers":{"properties":{"id":{"type":"string"}},"type":"object"}`
+	g.Expect(strings.Count(out.String(), headers)).To(Equal(4))
+	g.Expect(out.String()).ShouldNot(ContainSubstring(`"properties":{"properties"`))
+}
+
+func TestHeadersToSchema(t *testing.T) {
+	g := NewWithT(t)
+	var c converter
+	message := map[string]interface{}{
+		"headers": map[string]interface{}{
+			"type":       map[string]interface{}{"type": "string"},
+			"properties": map[string]interface{}{"type": "object"},
+		},
+	}
+	expected := map[string]interface{}{
+		"headers": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"type":       map[string]interface{}{"type": "string"},
+				"properties": map[string]interface{}{"type": "object"},
+			},
+		},
+	}
+	c.headersToSchema(message)
+	g.Expect(message).To(Equal(expected))
+	c.headersToSchema(message)
+	g.Expect(message).To(Equal(expected))
+}
+
+func TestConvert_canonical(t *testing.T) {
+	inputs, err := filepath.Glob("./testdata/input/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoders := map[string]Encode{
+		"json": encode.JSON(encode.Canonical(), encode.WithIndent(2)),
+		"yaml": encode.YAML(encode.Canonical(), encode.WithFlowLists(3)),
+	}
+	for _, inputFilePath := range inputs {
+		if info, err := os.Stat(inputFilePath); err != nil || info.IsDir() {
+			continue
+		}
+		for format, encoder := range encoders {
+			t.Run(fmt.Sprintf("%s to %s", inputFilePath, format), func(t *testing.T) {
+				g := NewWithT(t)
+				var expected, expectedWarnings string
+				for i := 0; i < 10; i++ {
+					var warnings []string
+					converter, err := New(decode.FromJSONWithYamlFallback, encoder,
+						WithProtocolBindings(nil),
+						WithServerEnvironments(MergeServerEnvironments),
+						WithStreamDiscriminator("type"),
+						WithComponentLifting(),
+						WithOperationIDs(nil),
+						WithWarningHandler(func(warning Warning) {
+							warnings = append(warnings, warning.String())
+						}),
+					)
+					g.Expect(err).ShouldNot(HaveOccurred())
+					result := convertFile(converter, inputFilePath, g)
+					if i == 0 {
+						expected, expectedWarnings = result, strings.Join(warnings, "\n")
+						continue
+					}
+					g.Expect(result).To(Equal(expected))
+					g.Expect(strings.Join(warnings, "\n")).To(Equal(expectedWarnings))
+				}
+			})
+		}
+	}
+}
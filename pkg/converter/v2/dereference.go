@@ -0,0 +1,187 @@
+package v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
+)
+
+// componentsRefPrefix is the prefix of the references expanded by the dereferencing.
+const componentsRefPrefix = "#/components/"
+
+// DefaultDereferenceLimits bound the dereferenced documents, unless WithDereferenceLimits is used.
+var DefaultDereferenceLimits = DereferenceLimits{
+	MaxBytes: 16 << 20,
+}
+
+// DereferenceLimits bound the dereferenced document, as a component referenced many times is copied
+// for every reference, so a small document can expand exponentially. Exceeding a limit results
+// in the LimitExceeded error from pkg/error. A zero limit is not checked.
+type DereferenceLimits struct {
+	// MaxBytes is the maximum size of the dereferenced document, estimated as the size of its compact JSON encoding.
+	MaxBytes int64
+	// MaxDepth is the maximum nesting depth of the mappings and sequences of the dereferenced document.
+	MaxDepth int
+}
+
+// WithDereferencing is a functional option that replaces every #/components/... reference of the converted document
+// with a copy of the referenced content, for the tools that cannot follow $ref. The recursive references are kept
+// and reported as warnings, as well as the references to components that do not exist.
+//
+// The dereferenced document is bounded by DefaultDereferenceLimits, see WithDereferenceLimits.
+func WithDereferencing() ConverterOption {
+	return func(converter *converter) error {
+		converter.dereference = true
+		return nil
+	}
+}
+
+// WithDereferenceLimits is a functional option that allows you to specify the limits of the dereferenced document.
+// It does not enable the dereferencing, see WithDereferencing.
+func WithDereferenceLimits(limits DereferenceLimits) ConverterOption {
+	return func(converter *converter) error {
+		if limits.MaxBytes < 0 || limits.MaxDepth < 0 {
+			return fmt.Errorf("invalid dereference limits: %+v", limits)
+		}
+		converter.dereferenceLimits = &limits
+		return nil
+	}
+}
+
+// dereferencer expands the references of a single conversion and measures the expanded document.
+type dereferencer struct {
+	*converter
+	limits DereferenceLimits
+	size   int64
+	err    error
+}
+
+// dereferenceComponents runs after all other conversion steps, so it expands the final components.
+func (c *converter) dereferenceComponents() error {
+	if !c.dereference {
+		return nil
+	}
+	d := dereferencer{
+		converter: c,
+		limits:    DefaultDereferenceLimits,
+	}
+	if c.dereferenceLimits != nil {
+		d.limits = *c.dereferenceLimits
+	}
+	data := d.dereferenceValue(c.data, "", 0, nil).(map[string]interface{})
+	if d.err != nil {
+		return d.err
+	}
+	if err := c.checkContext(); err != nil {
+		return err
+	}
+	c.data = data
+	return nil
+}
+
+// dereferenceValue returns a copy of the value with the references expanded. The refs are the references
+// being expanded at the path, used to detect the cycles. Once the context is done or a limit is exceeded,
+// the value is returned as it is, so the expansion of deeply nested references stops early.
+func (d *dereferencer) dereferenceValue(value interface{}, path string, depth int, refs []string) interface{} {
+	if d.err != nil || d.checkContext() != nil {
+		return value
+	}
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := value["$ref"].(string); ok && strings.HasPrefix(ref, componentsRefPrefix) {
+			return d.dereferenceRef(value, ref, path, depth, refs)
+		}
+		if isComponentPath(path) {
+			refs = append(refs[:len(refs):len(refs)], "#"+path)
+		}
+		if !d.enter(depth, 2) {
+			return value
+		}
+		result := make(map[string]interface{}, len(value))
+		for _, key := range sortedKeys(value) {
+			d.grow(int64(len(key)) + 4)
+			result[key] = d.dereferenceValue(value[key], path+"/"+escapePointer(key), depth+1, refs)
+		}
+		return result
+	case []interface{}:
+		if !d.enter(depth, int64(len(value))+2) {
+			return value
+		}
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = d.dereferenceValue(item, path+"/"+strconv.Itoa(i), depth+1, refs)
+		}
+		return result
+	default:
+		d.grow(scalarSize(value))
+		return value
+	}
+}
+
+func (d *dereferencer) dereferenceRef(value map[string]interface{}, ref, path string, depth int, refs []string) interface{} {
+	for _, expanded := range refs {
+		if expanded == ref {
+			d.warn(path, "kept recursive reference to %s", ref)
+			return d.copyRef(value)
+		}
+	}
+	target := d.lookupPointer(ref)
+	if target == nil {
+		d.warn(path, "kept reference to missing component %s", ref)
+		return d.copyRef(value)
+	}
+	return d.dereferenceValue(target, path, depth, append(refs[:len(refs):len(refs)], ref))
+}
+
+// enter checks the depth of the mapping or sequence entered at the depth and adds the size of its brackets.
+func (d *dereferencer) enter(depth int, size int64) bool {
+	if d.limits.MaxDepth > 0 && depth >= d.limits.MaxDepth {
+		d.err = asyncapierr.NewLimitExceeded("dereferenced depth", d.limits.MaxDepth)
+		return false
+	}
+	return d.grow(size)
+}
+
+// grow adds the size to the dereferenced document and checks the size limit.
+func (d *dereferencer) grow(size int64) bool {
+	d.size += size
+	if d.limits.MaxBytes > 0 && d.size > d.limits.MaxBytes {
+		d.err = asyncapierr.NewLimitExceeded("dereferenced size in bytes", d.limits.MaxBytes)
+		return false
+	}
+	return true
+}
+
+func (d *dereferencer) copyRef(value map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(value))
+	for key, item := range value {
+		d.grow(int64(len(key)) + 4 + scalarSize(item))
+		result[key] = item
+	}
+	return result
+}
+
+// scalarSize returns the size of the scalar in the compact JSON encoding, ignoring the escaping of the strings.
+func scalarSize(value interface{}) int64 {
+	switch value := value.(type) {
+	case string:
+		return int64(len(value)) + 2
+	case json.Number:
+		return int64(len(value))
+	case nil:
+		return 4
+	case bool:
+		return 5
+	default:
+		return int64(len(fmt.Sprint(value)))
+	}
+}
+
+// isComponentPath reports whether the path points to a component, for example /components/schemas/node,
+// so the references to the component found inside it are recursive.
+func isComponentPath(path string) bool {
+	return strings.HasPrefix("#"+path, componentsRefPrefix) && strings.Count(path, "/") == 3
+}
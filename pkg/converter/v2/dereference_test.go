@@ -0,0 +1,200 @@
+package v2
+
+import (
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
+	. "github.com/onsi/gomega"
+
+	"fmt"
+	"testing"
+)
+
+func TestDereferenceComponents(t *testing.T) {
+	ref := func(path string) map[string]interface{} {
+		return map[string]interface{}{"$ref": path}
+	}
+	tests := []struct {
+		name             string
+		data             map[string]interface{}
+		expectedChannels map[string]interface{}
+		expectedWarnings []string
+	}{
+		{
+			name: "nested references",
+			data: map[string]interface{}{
+				"channels": map[string]interface{}{
+					"a": map[string]interface{}{
+						"publish": map[string]interface{}{"message": ref("#/components/messages/m")},
+					},
+				},
+				"components": map[string]interface{}{
+					"messages": map[string]interface{}{
+						"m": map[string]interface{}{"payload": ref("#/components/schemas/s")},
+					},
+					"schemas": map[string]interface{}{
+						"s": map[string]interface{}{"type": "object", "properties": map[string]interface{}{
+							"at": ref("#/components/schemas/time"),
+						}},
+						"time": map[string]interface{}{"type": "string", "format": "date-time"},
+					},
+				},
+			},
+			expectedChannels: map[string]interface{}{
+				"a": map[string]interface{}{
+					"publish": map[string]interface{}{"message": map[string]interface{}{
+						"payload": map[string]interface{}{"type": "object", "properties": map[string]interface{}{
+							"at": map[string]interface{}{"type": "string", "format": "date-time"},
+						}},
+					}},
+				},
+			},
+		},
+		{
+			name: "recursive reference",
+			data: map[string]interface{}{
+				"channels": map[string]interface{}{
+					"a": map[string]interface{}{
+						"publish": map[string]interface{}{"message": map[string]interface{}{
+							"payload": ref("#/components/schemas/node"),
+						}},
+					},
+				},
+				"components": map[string]interface{}{
+					"schemas": map[string]interface{}{
+						"node": map[string]interface{}{"type": "object", "properties": map[string]interface{}{
+							"next": ref("#/components/schemas/node"),
+						}},
+					},
+				},
+			},
+			expectedChannels: map[string]interface{}{
+				"a": map[string]interface{}{
+					"publish": map[string]interface{}{"message": map[string]interface{}{
+						"payload": map[string]interface{}{"type": "object", "properties": map[string]interface{}{
+							"next": ref("#/components/schemas/node"),
+						}},
+					}},
+				},
+			},
+			expectedWarnings: []string{
+				"/channels/a/publish/message/payload/properties/next: kept recursive reference to #/components/schemas/node",
+				"/components/schemas/node/properties/next: kept recursive reference to #/components/schemas/node",
+			},
+		},
+		{
+			name: "missing and external references",
+			data: map[string]interface{}{
+				"channels": map[string]interface{}{
+					"a": map[string]interface{}{
+						"publish":   map[string]interface{}{"message": ref("#/components/messages/missing")},
+						"subscribe": map[string]interface{}{"message": ref("messages.yaml#/hello")},
+					},
+				},
+			},
+			expectedChannels: map[string]interface{}{
+				"a": map[string]interface{}{
+					"publish":   map[string]interface{}{"message": ref("#/components/messages/missing")},
+					"subscribe": map[string]interface{}{"message": ref("messages.yaml#/hello")},
+				},
+			},
+			expectedWarnings: []string{
+				"/channels/a/publish/message: kept reference to missing component #/components/messages/missing",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var warnings []string
+			c := converter{
+				dereference: true,
+				warningHandler: func(warning Warning) {
+					warnings = append(warnings, warning.String())
+				},
+				data: test.data,
+			}
+			g.Expect(c.dereferenceComponents()).To(Succeed())
+			g.Expect(c.data["channels"]).To(Equal(test.expectedChannels))
+			g.Expect(warnings).To(Equal(test.expectedWarnings))
+		})
+	}
+}
+
+func TestDereferenceComponents_copies(t *testing.T) {
+	g := NewWithT(t)
+	schema := map[string]interface{}{"type": "string"}
+	c := converter{
+		dereference: true,
+		data: map[string]interface{}{
+			"channels": map[string]interface{}{
+				"a": map[string]interface{}{"publish": map[string]interface{}{"message": map[string]interface{}{
+					"payload": map[string]interface{}{"$ref": "#/components/schemas/s"},
+				}}},
+			},
+			"components": map[string]interface{}{
+				"schemas": map[string]interface{}{"s": schema},
+			},
+		},
+	}
+	g.Expect(c.dereferenceComponents()).To(Succeed())
+	payload := c.data["channels"].(map[string]interface{})["a"].(map[string]interface{})["publish"].(map[string]interface{})["message"].(map[string]interface{})["payload"].(map[string]interface{})
+	payload["type"] = "integer"
+	g.Expect(schema).To(HaveKeyWithValue("type", "string"))
+}
+
+func TestDereferenceComponents_limits(t *testing.T) {
+	// every schema references the next one twice, so the dereferenced document doubles with each level
+	schemas := map[string]interface{}{
+		"s20": map[string]interface{}{"type": "string"},
+	}
+	for i := 0; i < 20; i++ {
+		next := map[string]interface{}{"$ref": fmt.Sprintf("#/components/schemas/s%d", i+1)}
+		schemas[fmt.Sprintf("s%d", i)] = map[string]interface{}{"type": "array", "items": []interface{}{next, next}}
+	}
+	defaultLimits := DefaultDereferenceLimits
+	defer func() {
+		DefaultDereferenceLimits = defaultLimits
+	}()
+	DefaultDereferenceLimits = DereferenceLimits{MaxBytes: 4096}
+	tests := []struct {
+		name     string
+		options  []ConverterOption
+		expected string
+	}{
+		{
+			name:     "default limits",
+			expected: "asyncapi: document exceeds the maximum dereferenced size in bytes of 4096",
+		},
+		{
+			name:     "max bytes",
+			options:  []ConverterOption{WithDereferenceLimits(DereferenceLimits{MaxBytes: 1024})},
+			expected: "asyncapi: document exceeds the maximum dereferenced size in bytes of 1024",
+		},
+		{
+			name:     "max depth",
+			options:  []ConverterOption{WithDereferenceLimits(DereferenceLimits{MaxDepth: 8})},
+			expected: "asyncapi: document exceeds the maximum dereferenced depth of 8",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := converter{
+				data: map[string]interface{}{
+					"components": map[string]interface{}{"schemas": schemas},
+				},
+			}
+			for _, option := range append(test.options, WithDereferencing()) {
+				g.Expect(option(&c)).To(Succeed())
+			}
+			err := c.dereferenceComponents()
+			g.Expect(asyncapierr.IsLimitExceeded(err)).To(BeTrue(), "%v", err)
+			g.Expect(err).To(MatchError(test.expected))
+		})
+	}
+}
+
+func TestWithDereferenceLimits_invalid(t *testing.T) {
+	g := NewWithT(t)
+	_, err := New(nil, nil, WithDereferenceLimits(DereferenceLimits{MaxBytes: -1}))
+	g.Expect(err).Should(HaveOccurred())
+}
@@ -0,0 +1,65 @@
+package v2
+
+import (
+	"io"
+
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
+)
+
+// StreamDecoder reads the AsyncAPI documents of a stream one by one, see decode.YamlStream.
+// Decode returns io.EOF when there are no more documents.
+type StreamDecoder interface {
+	Decode(v interface{}) error
+}
+
+// StreamEncoder writes the AsyncAPI documents into a stream one by one, see encode.YamlStream.
+type StreamEncoder interface {
+	Encode(v interface{}) error
+}
+
+// StreamConverter converts the streams of AsyncAPI documents, such as the YAML files holding several documents
+// separated by ---, from versions 1.0.0, 1.1.0 and 1.2.0 to version 2.0.0.
+type StreamConverter interface {
+	// ConvertStream converts the documents read from the decoder one by one and writes them to the encoder.
+	// It stops at the first document that fails and returns the DocumentError from pkg/error with its index.
+	ConvertStream(decoder StreamDecoder, encoder StreamEncoder) error
+}
+
+type streamConverter struct {
+	converter *converter
+}
+
+// NewStream creates a new stream converter. The documents are converted like with the converter
+// created by New with the same options.
+//
+// See ConverterOption.
+func NewStream(options ...ConverterOption) (StreamConverter, error) {
+	c, err := New(nil, nil, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &streamConverter{converter: c.(*converter)}, nil
+}
+
+func (s *streamConverter) ConvertStream(decoder StreamDecoder, encoder StreamEncoder) error {
+	for index := 0; ; index++ {
+		var document interface{}
+		decodeErr := decoder.Decode(&document)
+		if decodeErr == io.EOF {
+			return nil
+		}
+		s.converter.decode = func(v interface{}, _ io.Reader) error {
+			if decodeErr != nil {
+				return decodeErr
+			}
+			*v.(*interface{}) = document
+			return nil
+		}
+		s.converter.encode = func(v interface{}, _ io.Writer) error {
+			return encoder.Encode(v)
+		}
+		if err := s.converter.Convert(nil, nil); err != nil {
+			return asyncapierr.DocumentError{Index: index, Err: err}
+		}
+	}
+}
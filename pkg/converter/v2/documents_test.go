@@ -0,0 +1,89 @@
+package v2
+
+import (
+	"github.com/asyncapi/converter-go/pkg/decode"
+	"github.com/asyncapi/converter-go/pkg/encode"
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestStreamConverter_ConvertStream(t *testing.T) {
+	g := NewWithT(t)
+	var input bytes.Buffer
+	for _, path := range []string{
+		"./testdata/input/streetlights1.2.0.yaml",
+		"./testdata/input/gitter-streaming1.2.0.yaml",
+	} {
+		data, err := ioutil.ReadFile(path)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		input.WriteString("---\n")
+		input.Write(data)
+		input.WriteString("\n")
+	}
+	converter, err := NewStream()
+	g.Expect(err).ShouldNot(HaveOccurred())
+	var output bytes.Buffer
+	err = converter.ConvertStream(decode.NewYamlStream(&input), encode.NewYamlStream(&output))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	actual := decode.NewYamlStream(&output)
+	for _, path := range []string{
+		"./testdata/output/streetlights.yaml",
+		"./testdata/output/gitter-streaming.yaml",
+	} {
+		file, err := getFileReader(path)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		var expected, document interface{}
+		g.Expect(decode.FromYaml(&expected, file)).To(Succeed())
+		g.Expect(actual.Decode(&document)).To(Succeed())
+		g.Expect(document).To(Equal(expected))
+	}
+	var document interface{}
+	g.Expect(actual.Decode(&document)).To(Equal(io.EOF))
+}
+
+func TestStreamConverter_ConvertStream_error(t *testing.T) {
+	valid := "asyncapi: 1.2.0\ninfo: {title: a, version: 1.0.0}\ntopics: {a: {}}\n"
+	tests := []struct {
+		name     string
+		input    string
+		index    int
+		expected func(error) bool
+	}{
+		{
+			name:     "invalid property",
+			input:    valid + "---\nasyncapi: 1.2.0\ninfo: {title: a, version: 1.0.0}\n",
+			index:    1,
+			expected: asyncapierr.IsInvalidProperty,
+		},
+		{
+			name:     "invalid document",
+			input:    valid + "---\n" + valid + "---\ntopics: [\n",
+			index:    2,
+			expected: asyncapierr.IsInvalidDocument,
+		},
+		{
+			name:     "up to date document",
+			input:    "asyncapi: 2.0.0\n---\n" + valid,
+			index:    0,
+			expected: asyncapierr.IsDocumentVersionUpToDate,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			converter, err := NewStream()
+			g.Expect(err).ShouldNot(HaveOccurred())
+			err = converter.ConvertStream(decode.NewYamlStream(strings.NewReader(test.input)), encode.NewYamlStream(ioutil.Discard))
+			g.Expect(err).To(BeAssignableToTypeOf(asyncapierr.DocumentError{}))
+			g.Expect(err.(asyncapierr.DocumentError).Index).To(Equal(test.index))
+			g.Expect(test.expected(err)).To(BeTrue())
+		})
+	}
+}
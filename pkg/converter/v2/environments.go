@@ -0,0 +1,205 @@
+package v2
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// ServerEnvironments defines how the servers whose URLs differ only in a single part,
+// for example api.dev.example.com and api.prod.example.com, are converted.
+type ServerEnvironments int
+
+const (
+	// KeepServerEnvironments copies the servers one-to-one. It is the default.
+	KeepServerEnvironments ServerEnvironments = iota
+	// MergeServerEnvironments merges the servers into a single server with the environment URL variable,
+	// for example api.{environment}.example.com, that enumerates the differing parts of the URLs.
+	MergeServerEnvironments
+	// TagServerEnvironments names the servers after the differing parts of their URLs, for example dev and prod.
+	TagServerEnvironments
+)
+
+// environmentVariable is the name of the server variable created by MergeServerEnvironments.
+const environmentVariable = "environment"
+
+var urlTokenRegexp = regexp.MustCompile(`[^./:]+|[./:]`)
+
+// environmentIgnoredFields are the server fields that may differ between the servers of one environment group.
+var environmentIgnoredFields = []string{"url", "description", "x-name"}
+
+// WithServerEnvironments is a functional option that allows you to specify how the servers
+// whose URLs differ only in a single part are converted.
+func WithServerEnvironments(environments ServerEnvironments) ConverterOption {
+	return func(converter *converter) error {
+		converter.serverEnvironments = environments
+		return nil
+	}
+}
+
+// ServerEnvironmentsByName returns the ServerEnvironments with the name: keep, merge or tag.
+func ServerEnvironmentsByName(name string) (ServerEnvironments, bool) {
+	switch name {
+	case "keep":
+		return KeepServerEnvironments, true
+	case "merge":
+		return MergeServerEnvironments, true
+	case "tag":
+		return TagServerEnvironments, true
+	default:
+		return KeepServerEnvironments, false
+	}
+}
+
+// serverGroup holds the indexes of the servers whose URLs differ only in the token at the position.
+type serverGroup struct {
+	indexes  []int
+	tokens   []string
+	position int
+}
+
+func (g serverGroup) environment(servers []interface{}, i int) string {
+	url, _ := servers[g.indexes[i]].(map[string]interface{})["url"].(string)
+	return urlTokenRegexp.FindAllString(url, -1)[g.position]
+}
+
+// groupServers groups the servers differing only in one part of the URL.
+// Servers that do not belong to any group are returned as single-element groups.
+// The groups are ordered by the index of their first server, so the result is deterministic.
+func groupServers(servers []interface{}) []serverGroup {
+	tokens := make([][]string, len(servers))
+	for i, item := range servers {
+		url, _ := item.(map[string]interface{})["url"].(string)
+		tokens[i] = urlTokenRegexp.FindAllString(url, -1)
+	}
+
+	grouped := make([]bool, len(servers))
+	var groups []serverGroup
+	for i := range servers {
+		if grouped[i] {
+			continue
+		}
+		grouped[i] = true
+		best := serverGroup{indexes: []int{i}, tokens: tokens[i], position: -1}
+		for position, token := range tokens[i] {
+			if isURLDelimiter(token) || strings.HasPrefix(token, "{") {
+				continue
+			}
+			candidate := serverGroup{indexes: []int{i}, tokens: tokens[i], position: position}
+			for j := i + 1; j < len(servers); j++ {
+				if !grouped[j] && sameEnvironmentGroup(servers[i], servers[j], tokens[i], tokens[j], position) {
+					candidate.indexes = append(candidate.indexes, j)
+				}
+			}
+			if len(candidate.indexes) > len(best.indexes) {
+				best = candidate
+			}
+		}
+		for _, index := range best.indexes {
+			grouped[index] = true
+		}
+		groups = append(groups, best)
+	}
+	return groups
+}
+
+func isURLDelimiter(token string) bool {
+	return token == "." || token == "/" || token == ":"
+}
+
+func sameEnvironmentGroup(first, second interface{}, firstTokens, secondTokens []string, position int) bool {
+	if len(firstTokens) != len(secondTokens) {
+		return false
+	}
+	for i := range firstTokens {
+		if i != position && firstTokens[i] != secondTokens[i] {
+			return false
+		}
+	}
+	if firstTokens[position] == secondTokens[position] || strings.HasPrefix(secondTokens[position], "{") {
+		return false
+	}
+	return reflect.DeepEqual(withoutFields(first, environmentIgnoredFields), withoutFields(second, environmentIgnoredFields))
+}
+
+func withoutFields(item interface{}, fields []string) map[string]interface{} {
+	result := make(map[string]interface{})
+	for key, value := range item.(map[string]interface{}) {
+		result[key] = value
+	}
+	for _, field := range fields {
+		delete(result, field)
+	}
+	return result
+}
+
+// mergeServerGroup merges the servers of the group into the first one, replacing the differing part
+// of the URL with the environment variable.
+func mergeServerGroup(servers []interface{}, group serverGroup) map[string]interface{} {
+	merged := servers[group.indexes[0]].(map[string]interface{})
+	variables, ok := merged["variables"].(map[string]interface{})
+	if !ok {
+		variables = make(map[string]interface{})
+	}
+	name := environmentVariable
+	for i := 2; variables[name] != nil; i++ {
+		name = fmt.Sprintf("%s%d", environmentVariable, i)
+	}
+
+	var environments []interface{}
+	for i := range group.indexes {
+		environments = append(environments, group.environment(servers, i))
+	}
+	variables[name] = map[string]interface{}{
+		"enum":    environments,
+		"default": environments[0],
+	}
+	merged["variables"] = variables
+
+	tokens := make([]string, len(group.tokens))
+	copy(tokens, group.tokens)
+	tokens[group.position] = fmt.Sprintf("{%s}", name)
+	merged["url"] = strings.Join(tokens, "")
+	return merged
+}
+
+// nameServers names the converted servers, grouping them according to the ServerEnvironments option.
+func (c *converter) nameServers(servers []interface{}) (map[string]interface{}, error) {
+	groups := make([]serverGroup, len(servers))
+	for i := range servers {
+		groups[i] = serverGroup{indexes: []int{i}, position: -1}
+	}
+	if c.serverEnvironments != KeepServerEnvironments {
+		groups = groupServers(servers)
+	}
+
+	mappedServers := make(map[string]interface{})
+	usedNames := make(map[string]bool)
+	for _, group := range groups {
+		switch {
+		case len(group.indexes) > 1 && c.serverEnvironments == MergeServerEnvironments:
+			merged := mergeServerGroup(servers, group)
+			name, err := c.serverName(group.indexes[0], merged, usedNames)
+			if err != nil {
+				return nil, err
+			}
+			mappedServers[name] = merged
+		case len(group.indexes) > 1 && c.serverEnvironments == TagServerEnvironments:
+			for i, index := range group.indexes {
+				name := uniqueServerName(sanitizeServerName(group.environment(servers, i)), usedNames)
+				usedNames[name] = true
+				mappedServers[name] = servers[index]
+			}
+		default:
+			for _, index := range group.indexes {
+				name, err := c.serverName(index, servers[index].(map[string]interface{}), usedNames)
+				if err != nil {
+					return nil, err
+				}
+				mappedServers[name] = servers[index]
+			}
+		}
+	}
+	return mappedServers, nil
+}
@@ -0,0 +1,151 @@
+package v2
+
+import (
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func testEnvironmentServers() []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"url":         "api.dev.example.com:{port}",
+			"scheme":      "mqtt",
+			"description": "Development server",
+			"variables": map[string]interface{}{
+				"port": map[string]interface{}{"default": "1883"},
+			},
+		},
+		map[string]interface{}{
+			"url":    "broker.example.com",
+			"scheme": "mqtt",
+		},
+		map[string]interface{}{
+			"url":         "api.prod.example.com:{port}",
+			"scheme":      "mqtt",
+			"description": "Production server",
+			"variables": map[string]interface{}{
+				"port": map[string]interface{}{"default": "1883"},
+			},
+		},
+		map[string]interface{}{
+			"url":    "api.staging.example.com:{port}",
+			"scheme": "amqp",
+			"variables": map[string]interface{}{
+				"port": map[string]interface{}{"default": "1883"},
+			},
+		},
+	}
+}
+
+func TestUpdateServers_environments(t *testing.T) {
+	tests := []struct {
+		name         string
+		environments ServerEnvironments
+		expected     map[string]interface{}
+	}{
+		{
+			name:         "keep",
+			environments: KeepServerEnvironments,
+			expected: map[string]interface{}{
+				"default": HaveKeyWithValue("url", "api.dev.example.com:{port}"),
+				"server1": HaveKeyWithValue("url", "broker.example.com"),
+				"server2": HaveKeyWithValue("url", "api.prod.example.com:{port}"),
+				"server3": HaveKeyWithValue("url", "api.staging.example.com:{port}"),
+			},
+		},
+		{
+			name:         "merge",
+			environments: MergeServerEnvironments,
+			expected: map[string]interface{}{
+				"default": And(
+					HaveKeyWithValue("url", "api.{environment}.example.com:{port}"),
+					HaveKeyWithValue("description", "Development server"),
+					HaveKeyWithValue("variables", map[string]interface{}{
+						"port": map[string]interface{}{"default": "1883"},
+						"environment": map[string]interface{}{
+							"enum":    []interface{}{"dev", "prod"},
+							"default": "dev",
+						},
+					}),
+				),
+				"server1": HaveKeyWithValue("url", "broker.example.com"),
+				"server3": HaveKeyWithValue("url", "api.staging.example.com:{port}"),
+			},
+		},
+		{
+			name:         "tag",
+			environments: TagServerEnvironments,
+			expected: map[string]interface{}{
+				"dev":     HaveKeyWithValue("url", "api.dev.example.com:{port}"),
+				"prod":    HaveKeyWithValue("url", "api.prod.example.com:{port}"),
+				"server1": HaveKeyWithValue("url", "broker.example.com"),
+				"server3": HaveKeyWithValue("url", "api.staging.example.com:{port}"),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := converter{
+				serverEnvironments: test.environments,
+				data: map[string]interface{}{
+					"servers": testEnvironmentServers(),
+				},
+			}
+			g.Expect(c.updateServers()).To(Succeed())
+			servers := c.data["servers"].(map[string]interface{})
+			g.Expect(servers).To(HaveLen(len(test.expected)))
+			for name, matcher := range test.expected {
+				g.Expect(servers).To(HaveKeyWithValue(name, matcher))
+			}
+		})
+	}
+}
+
+func TestGroupServers(t *testing.T) {
+	tests := []struct {
+		name     string
+		urls     []string
+		expected [][]int
+	}{
+		{
+			name:     "path segment",
+			urls:     []string{"example.com/v1", "example.com/v2", "example.com/v2/x"},
+			expected: [][]int{{0, 1}, {2}},
+		},
+		{
+			name:     "largest group wins",
+			urls:     []string{"a.example.com", "b.example.com", "c.example.com", "a.example.org"},
+			expected: [][]int{{0, 1, 2}, {3}},
+		},
+		{
+			name:     "variables are not environments",
+			urls:     []string{"{host}.example.com", "dev.example.com"},
+			expected: [][]int{{0}, {1}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var servers []interface{}
+			for _, url := range test.urls {
+				servers = append(servers, map[string]interface{}{"url": url})
+			}
+			var indexes [][]int
+			for _, group := range groupServers(servers) {
+				indexes = append(indexes, group.indexes)
+			}
+			g.Expect(indexes).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestServerEnvironmentsByName(t *testing.T) {
+	g := NewWithT(t)
+	environments, ok := ServerEnvironmentsByName("merge")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(environments).To(Equal(MergeServerEnvironments))
+	_, ok = ServerEnvironmentsByName("unknown")
+	g.Expect(ok).To(BeFalse())
+}
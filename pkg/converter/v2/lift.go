@@ -0,0 +1,131 @@
+package v2
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var nonWordRegexp = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// WithComponentLifting is a functional option that moves the inline messages of the channel operations
+// to components.messages and their inline payload schemas to components.schemas, replacing them with $ref.
+// The components are named after the channel and the operation, for example the publish message
+// of the user/signedup channel is named userSignedupPublish and its payload userSignedupPublishPayload.
+// Content-identical components are stored once.
+func WithComponentLifting() ConverterOption {
+	return func(converter *converter) error {
+		converter.liftComponents = true
+		return nil
+	}
+}
+
+// liftInlineComponents runs after updateComponents, so the headers of the lifted messages are not converted twice.
+func (c *converter) liftInlineComponents() error {
+	if !c.liftComponents {
+		return nil
+	}
+	channels, _ := c.data["channels"].(map[string]interface{})
+	for _, channelName := range sortedKeys(channels) {
+		if err := c.checkContext(); err != nil {
+			return err
+		}
+		channel, ok := channels[channelName].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, operationName := range topicOperations {
+			operation, ok := channel[operationName].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name := componentName(channelName, operationName)
+			message, ok := operation["message"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			oneOf, ok := message["oneOf"].([]interface{})
+			if !ok {
+				operation["message"] = c.liftMessage(name, message)
+				continue
+			}
+			for i, item := range oneOf {
+				if element, ok := item.(map[string]interface{}); ok {
+					oneOf[i] = c.liftMessage(fmt.Sprintf("%s%d", name, i+1), element)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// liftMessage moves the inline message and its inline payload to the components and returns the reference to the message.
+func (c *converter) liftMessage(name string, message map[string]interface{}) map[string]interface{} {
+	if _, ok := message["$ref"]; ok {
+		return message
+	}
+	if payload, ok := message["payload"].(map[string]interface{}); ok {
+		if _, ok := payload["$ref"]; !ok {
+			message["payload"] = c.liftComponent("schemas", name+"Payload", payload)
+		}
+	}
+	return c.liftComponent("messages", name, message)
+}
+
+// liftComponent stores the value in the components of the kind, unless a content-identical component exists,
+// and returns the reference to the component.
+func (c *converter) liftComponent(kind, name string, value map[string]interface{}) map[string]interface{} {
+	components, ok := c.data["components"].(map[string]interface{})
+	if !ok {
+		components = make(map[string]interface{})
+		c.data["components"] = components
+	}
+	items, ok := components[kind].(map[string]interface{})
+	if !ok {
+		items = make(map[string]interface{})
+		components[kind] = items
+	}
+
+	key := ""
+	for _, existing := range sortedKeys(items) {
+		if reflect.DeepEqual(items[existing], value) {
+			key = existing
+			break
+		}
+	}
+	if key == "" {
+		key = name
+		for i := 2; items[key] != nil; i++ {
+			key = fmt.Sprintf("%s%d", name, i)
+		}
+		items[key] = value
+	}
+	return map[string]interface{}{
+		"$ref": fmt.Sprintf("#/components/%s/%s", kind, escapePointer(key)),
+	}
+}
+
+// componentName returns the lowerCamelCase name made of the words of the channel and the operation,
+// for example user/{userId}/signedup and publish result in userUserIdSignedupPublish.
+func componentName(channel, operation string) string {
+	return camelCase(channel, operation)
+}
+
+// nameWords returns the alphanumeric words of the parts.
+func nameWords(parts ...string) []string {
+	return strings.Fields(nonWordRegexp.ReplaceAllString(strings.Join(parts, " "), " "))
+}
+
+// camelCase joins the words of the parts into the lowerCamelCase name.
+func camelCase(parts ...string) string {
+	words := nameWords(parts...)
+	for i, word := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(word[:1]) + word[1:]
+		} else {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, "")
+}
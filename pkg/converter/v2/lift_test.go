@@ -0,0 +1,201 @@
+package v2
+
+import (
+	"github.com/asyncapi/converter-go/pkg/decode"
+	"github.com/asyncapi/converter-go/pkg/encode"
+	. "github.com/onsi/gomega"
+
+	"encoding/json"
+	"testing"
+)
+
+func TestLiftInlineComponents(t *testing.T) {
+	payload := func(kind string) map[string]interface{} {
+		return map[string]interface{}{"type": kind}
+	}
+	ref := func(path string) map[string]interface{} {
+		return map[string]interface{}{"$ref": path}
+	}
+	tests := []struct {
+		name               string
+		channels           map[string]interface{}
+		components         map[string]interface{}
+		expectedChannels   map[string]interface{}
+		expectedComponents map[string]interface{}
+	}{
+		{
+			name: "message and payload",
+			channels: map[string]interface{}{
+				"user/signedup": map[string]interface{}{
+					"publish": map[string]interface{}{
+						"message": map[string]interface{}{"summary": "a", "payload": payload("object")},
+					},
+				},
+			},
+			expectedChannels: map[string]interface{}{
+				"user/signedup": map[string]interface{}{
+					"publish": map[string]interface{}{
+						"message": ref("#/components/messages/userSignedupPublish"),
+					},
+				},
+			},
+			expectedComponents: map[string]interface{}{
+				"messages": map[string]interface{}{
+					"userSignedupPublish": map[string]interface{}{
+						"summary": "a",
+						"payload": ref("#/components/schemas/userSignedupPublishPayload"),
+					},
+				},
+				"schemas": map[string]interface{}{
+					"userSignedupPublishPayload": payload("object"),
+				},
+			},
+		},
+		{
+			name: "oneOf and references",
+			channels: map[string]interface{}{
+				"/": map[string]interface{}{
+					"subscribe": map[string]interface{}{
+						"message": map[string]interface{}{"oneOf": []interface{}{
+							ref("#/components/messages/hello"),
+							map[string]interface{}{"payload": ref("#/components/schemas/event")},
+						}},
+					},
+				},
+			},
+			expectedChannels: map[string]interface{}{
+				"/": map[string]interface{}{
+					"subscribe": map[string]interface{}{
+						"message": map[string]interface{}{"oneOf": []interface{}{
+							ref("#/components/messages/hello"),
+							ref("#/components/messages/subscribe2"),
+						}},
+					},
+				},
+			},
+			expectedComponents: map[string]interface{}{
+				"messages": map[string]interface{}{
+					"subscribe2": map[string]interface{}{"payload": ref("#/components/schemas/event")},
+				},
+			},
+		},
+		{
+			name: "duplicates",
+			channels: map[string]interface{}{
+				"a": map[string]interface{}{
+					"publish":   map[string]interface{}{"message": map[string]interface{}{"payload": payload("string")}},
+					"subscribe": map[string]interface{}{"message": map[string]interface{}{"payload": payload("string")}},
+				},
+				"b": map[string]interface{}{
+					"publish": map[string]interface{}{"message": map[string]interface{}{"summary": "b", "payload": payload("string")}},
+				},
+			},
+			expectedChannels: map[string]interface{}{
+				"a": map[string]interface{}{
+					"publish":   map[string]interface{}{"message": ref("#/components/messages/aPublish")},
+					"subscribe": map[string]interface{}{"message": ref("#/components/messages/aPublish")},
+				},
+				"b": map[string]interface{}{
+					"publish": map[string]interface{}{"message": ref("#/components/messages/bPublish")},
+				},
+			},
+			expectedComponents: map[string]interface{}{
+				"messages": map[string]interface{}{
+					"aPublish": map[string]interface{}{"payload": ref("#/components/schemas/aPublishPayload")},
+					"bPublish": map[string]interface{}{"summary": "b", "payload": ref("#/components/schemas/aPublishPayload")},
+				},
+				"schemas": map[string]interface{}{
+					"aPublishPayload": payload("string"),
+				},
+			},
+		},
+		{
+			name: "existing components",
+			channels: map[string]interface{}{
+				"a": map[string]interface{}{
+					"publish":   map[string]interface{}{"message": map[string]interface{}{"payload": payload("string")}},
+					"subscribe": map[string]interface{}{"message": map[string]interface{}{"payload": payload("integer")}},
+				},
+			},
+			components: map[string]interface{}{
+				"schemas": map[string]interface{}{
+					"text":              payload("string"),
+					"aSubscribePayload": payload("boolean"),
+				},
+			},
+			expectedChannels: map[string]interface{}{
+				"a": map[string]interface{}{
+					"publish":   map[string]interface{}{"message": ref("#/components/messages/aPublish")},
+					"subscribe": map[string]interface{}{"message": ref("#/components/messages/aSubscribe")},
+				},
+			},
+			expectedComponents: map[string]interface{}{
+				"messages": map[string]interface{}{
+					"aPublish":   map[string]interface{}{"payload": ref("#/components/schemas/text")},
+					"aSubscribe": map[string]interface{}{"payload": ref("#/components/schemas/aSubscribePayload2")},
+				},
+				"schemas": map[string]interface{}{
+					"text":               payload("string"),
+					"aSubscribePayload":  payload("boolean"),
+					"aSubscribePayload2": payload("integer"),
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := converter{
+				liftComponents: true,
+				data: map[string]interface{}{
+					"channels": test.channels,
+				},
+			}
+			if test.components != nil {
+				c.data["components"] = test.components
+			}
+			g.Expect(c.liftInlineComponents()).To(Succeed())
+			g.Expect(c.data["channels"]).To(Equal(test.expectedChannels))
+			g.Expect(c.data["components"]).To(Equal(test.expectedComponents))
+		})
+	}
+}
+
+func TestLiftInlineComponents_disabled(t *testing.T) {
+	g := NewWithT(t)
+	channels := map[string]interface{}{
+		"a": map[string]interface{}{
+			"publish": map[string]interface{}{"message": map[string]interface{}{"summary": "a"}},
+		},
+	}
+	c := converter{
+		data: map[string]interface{}{
+			"channels": channels,
+		},
+	}
+	g.Expect(c.liftInlineComponents()).To(Succeed())
+	g.Expect(c.data).ShouldNot(HaveKey("components"))
+}
+
+func TestComponentName(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(componentName("smartylighting/streetlights/1/0/event/{streetlightId}/lighting/measured", "publish")).
+		To(Equal("smartylightingStreetlights10EventStreetlightIdLightingMeasuredPublish"))
+	g.Expect(componentName("User.Events", "subscribe")).To(Equal("userEventsSubscribe"))
+}
+
+func TestConvert_componentLifting_headers(t *testing.T) {
+	g := NewWithT(t)
+	converter, err := New(decode.FromJSONWithYamlFallback, encode.ToJSON, WithComponentLifting())
+	g.Expect(err).ShouldNot(HaveOccurred())
+	result := convertFile(converter, "./testdata/input/streetlights1.2.0_headers_in_operation.yaml", g)
+
+	var document map[string]interface{}
+	g.Expect(json.Unmarshal([]byte(result), &document)).To(Succeed())
+	name := "smartylightingStreetlights10EventStreetlightIdLightingMeasuredPublish"
+	messages := document["components"].(map[string]interface{})["messages"].(map[string]interface{})
+	g.Expect(messages).To(HaveKeyWithValue(name, HaveKeyWithValue("headers", And(
+		HaveKeyWithValue("type", "object"),
+		HaveKeyWithValue("properties", HaveKey("MQMD")),
+	))))
+}
@@ -0,0 +1,95 @@
+package v2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OperationIDNamer returns the operationId of the publish or subscribe operation of the channel.
+// If the returned ID is empty, the operation is named with CamelCaseOperationIDs.
+// Duplicated IDs are resolved by the converter, so the returned IDs do not have to be unique.
+type OperationIDNamer func(operation, channel string) (string, error)
+
+// WithOperationIDs is a functional option that fills the operationId of the converted publish and subscribe
+// operations that do not define it. The IDs are unique in the document. If namer is nil, CamelCaseOperationIDs is used.
+func WithOperationIDs(namer OperationIDNamer) ConverterOption {
+	return func(converter *converter) error {
+		if namer == nil {
+			namer = CamelCaseOperationIDs
+		}
+		converter.operationIDNamer = namer
+		return nil
+	}
+}
+
+// CamelCaseOperationIDs names the operations with the lowerCamelCase of the operation and the channel,
+// for example publish and user/{userId}/signedup result in publishUserUserIdSignedup.
+func CamelCaseOperationIDs(operation, channel string) (string, error) {
+	return camelCase(operation, channel), nil
+}
+
+// SnakeCaseOperationIDs names the operations with the snake_case of the operation and the channel,
+// for example publish and user/{userId}/signedup result in publish_user_userid_signedup.
+func SnakeCaseOperationIDs(operation, channel string) (string, error) {
+	return strings.ToLower(strings.Join(nameWords(operation, channel), "_")), nil
+}
+
+// OperationIDNamerByName returns the built-in OperationIDNamer with the name: camel or snake.
+func OperationIDNamerByName(name string) (OperationIDNamer, bool) {
+	switch name {
+	case "camel":
+		return CamelCaseOperationIDs, true
+	case "snake":
+		return SnakeCaseOperationIDs, true
+	default:
+		return nil, false
+	}
+}
+
+// updateOperationIDs fills the operation IDs. The channels are visited in order, so the IDs are deterministic.
+func (c *converter) updateOperationIDs() error {
+	if c.operationIDNamer == nil {
+		return nil
+	}
+	channels, _ := c.data["channels"].(map[string]interface{})
+	used := make(map[string]bool)
+	var anonymous []map[string]interface{}
+	var names []string
+	for _, channelName := range sortedKeys(channels) {
+		if err := c.checkContext(); err != nil {
+			return err
+		}
+		channel, ok := channels[channelName].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, operationName := range topicOperations {
+			operation, ok := channel[operationName].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if id, ok := operation["operationId"].(string); ok {
+				used[id] = true
+				continue
+			}
+			id, err := c.operationIDNamer(operationName, channelName)
+			if err != nil {
+				return err
+			}
+			if id == "" {
+				id, _ = CamelCaseOperationIDs(operationName, channelName)
+			}
+			anonymous = append(anonymous, operation)
+			names = append(names, id)
+		}
+	}
+	for i, operation := range anonymous {
+		id := names[i]
+		for suffix := 2; used[id]; suffix++ {
+			id = fmt.Sprintf("%s%d", names[i], suffix)
+		}
+		used[id] = true
+		operation["operationId"] = id
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package v2
+
+import (
+	. "github.com/onsi/gomega"
+
+	"errors"
+	"testing"
+)
+
+func TestUpdateOperationIDs(t *testing.T) {
+	tests := []struct {
+		name      string
+		namer     OperationIDNamer
+		data      map[string]interface{}
+		expected  map[string]map[string]string
+		shouldErr bool
+	}{
+		{
+			name: "topics",
+			data: map[string]interface{}{
+				"baseTopic": "user",
+				"topics": map[string]interface{}{
+					"{userId}.signedup": map[string]interface{}{
+						"publish":   map[string]interface{}{},
+						"subscribe": map[string]interface{}{},
+					},
+				},
+			},
+			expected: map[string]map[string]string{
+				"user/{userId}/signedup": {
+					"publish":   "publishUserUserIdSignedup",
+					"subscribe": "subscribeUserUserIdSignedup",
+				},
+			},
+		},
+		{
+			name:  "snake case stream",
+			namer: SnakeCaseOperationIDs,
+			data: map[string]interface{}{
+				"stream": map[string]interface{}{
+					"read":  []interface{}{map[string]interface{}{}},
+					"write": []interface{}{map[string]interface{}{}},
+				},
+			},
+			expected: map[string]map[string]string{
+				"/": {
+					"publish":   "publish",
+					"subscribe": "subscribe",
+				},
+			},
+		},
+		{
+			name: "unique",
+			data: map[string]interface{}{
+				"topics": map[string]interface{}{
+					"user.signedup": map[string]interface{}{"publish": map[string]interface{}{}},
+					"user-signedup": map[string]interface{}{"publish": map[string]interface{}{}},
+					"user_signedup": map[string]interface{}{"publish": map[string]interface{}{}},
+				},
+			},
+			expected: map[string]map[string]string{
+				"user-signedup": {"publish": "publishUserSignedup"},
+				"user/signedup": {"publish": "publishUserSignedup2"},
+				"user_signedup": {"publish": "publishUserSignedup3"},
+			},
+		},
+		{
+			name: "empty name",
+			namer: func(operation, channel string) (string, error) {
+				return "", nil
+			},
+			data: map[string]interface{}{
+				"events": map[string]interface{}{
+					"receive": []interface{}{map[string]interface{}{}},
+				},
+			},
+			expected: map[string]map[string]string{
+				"/": {"subscribe": "subscribe"},
+			},
+		},
+		{
+			name: "error",
+			namer: func(operation, channel string) (string, error) {
+				return "", errors.New("test error")
+			},
+			data: map[string]interface{}{
+				"events": map[string]interface{}{
+					"receive": []interface{}{map[string]interface{}{}},
+				},
+			},
+			shouldErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := converter{data: test.data}
+			g.Expect(WithOperationIDs(test.namer)(&c)).To(Succeed())
+			g.Expect(c.createChannels()).To(Succeed())
+			g.Expect(c.alterChannels()).To(Succeed())
+			err := c.updateOperationIDs()
+			if test.shouldErr {
+				g.Expect(err).Should(HaveOccurred())
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+			channels := c.data["channels"].(map[string]interface{})
+			g.Expect(channels).To(HaveLen(len(test.expected)))
+			for channel, operations := range test.expected {
+				for operation, id := range operations {
+					g.Expect(channels).To(HaveKeyWithValue(channel, HaveKeyWithValue(operation, HaveKeyWithValue("operationId", id))))
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateOperationIDs_existing(t *testing.T) {
+	g := NewWithT(t)
+	c := converter{
+		operationIDNamer: CamelCaseOperationIDs,
+		data: map[string]interface{}{
+			"channels": map[string]interface{}{
+				"a": map[string]interface{}{"publish": map[string]interface{}{}},
+				"b": map[string]interface{}{"publish": map[string]interface{}{"operationId": "publishA"}},
+			},
+		},
+	}
+	g.Expect(c.updateOperationIDs()).To(Succeed())
+	g.Expect(c.data["channels"]).To(HaveKeyWithValue("a", HaveKeyWithValue("publish", HaveKeyWithValue("operationId", "publishA2"))))
+	g.Expect(c.data["channels"]).To(HaveKeyWithValue("b", HaveKeyWithValue("publish", HaveKeyWithValue("operationId", "publishA"))))
+}
+
+func TestOperationIDNamerByName(t *testing.T) {
+	g := NewWithT(t)
+	for _, name := range []string{"camel", "snake"} {
+		namer, ok := OperationIDNamerByName(name)
+		g.Expect(ok).To(BeTrue(), name)
+		g.Expect(namer).ShouldNot(BeNil(), name)
+	}
+	_, ok := OperationIDNamerByName("kebab")
+	g.Expect(ok).To(BeFalse())
+}
@@ -0,0 +1,159 @@
+package v2
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// securityExtension is the root extension that holds the top-level security requirements
+// of documents without servers, as AsyncAPI 2.0.0 defines the security requirements only on servers.
+const securityExtension = "x-security"
+
+// securitySchemeTypes maps the lowercase 1.x security scheme types to the 2.0.0 ones.
+var securitySchemeTypes = map[string]string{
+	"userpassword":         "userPassword",
+	"apikey":               "apiKey",
+	"x509":                 "X509",
+	"symmetricencryption":  "symmetricEncryption",
+	"asymmetricencryption": "asymmetricEncryption",
+	"httpapikey":           "httpApiKey",
+	"http":                 "http",
+	"oauth2":               "oauth2",
+	"openidconnect":        "openIdConnect",
+}
+
+// httpSchemeTypes are the 1.x shorthand security scheme types converted to the http type with the scheme.
+var httpSchemeTypes = map[string]string{
+	"basic":  "basic",
+	"bearer": "bearer",
+}
+
+// apiKeyLocations are the locations of the apiKey scheme that are valid only for the httpApiKey type.
+var apiKeyLocations = map[string]bool{
+	"query":  true,
+	"header": true,
+	"cookie": true,
+}
+
+// updateSecuritySchemes converts the types of the security schemes defined in components.securitySchemes.
+// Schemes with unknown types are kept unchanged and reported as warnings.
+func (c *converter) updateSecuritySchemes() error {
+	components, ok := c.data["components"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	schemes, ok := components["securitySchemes"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for _, name := range sortedKeys(schemes) {
+		item := schemes[name]
+		path := fmt.Sprintf("/components/securitySchemes/%s", escapePointer(name))
+		scheme, ok := item.(map[string]interface{})
+		if !ok {
+			c.warn(path, "dropped malformed security scheme")
+			delete(schemes, name)
+			continue
+		}
+		if _, ok := scheme["$ref"]; ok {
+			continue
+		}
+		if !convertSecurityScheme(scheme) {
+			c.warn(path, "kept security scheme of unsupported type %v unchanged", scheme["type"])
+		}
+	}
+	return nil
+}
+
+// convertSecurityScheme converts the type of the security scheme and reports whether the type is supported.
+// The schemes of unsupported types are not changed.
+func convertSecurityScheme(scheme map[string]interface{}) bool {
+	schemeType, _ := scheme["type"].(string)
+	lowerType := strings.ToLower(schemeType)
+	if httpScheme, ok := httpSchemeTypes[lowerType]; ok {
+		scheme["type"] = "http"
+		if _, ok := scheme["scheme"]; !ok {
+			scheme["scheme"] = httpScheme
+		}
+		return true
+	}
+	converted, ok := securitySchemeTypes[lowerType]
+	if !ok {
+		return false
+	}
+	if location, _ := scheme["in"].(string); converted == "apiKey" && apiKeyLocations[location] {
+		converted = "httpApiKey"
+	}
+	scheme["type"] = converted
+	return true
+}
+
+// updateSecurity drops the top-level security requirements referring to security schemes that are not defined
+// in components.securitySchemes, as well as the duplicated requirements. The security requirements of documents
+// without servers are moved to the x-security extension.
+func (c *converter) updateSecurity() error {
+	requirements, ok := c.data["security"].([]interface{})
+	if !ok {
+		return nil
+	}
+	var schemes map[string]interface{}
+	if components, ok := c.data["components"].(map[string]interface{}); ok {
+		schemes, _ = components["securitySchemes"].(map[string]interface{})
+	}
+
+	var security []interface{}
+	for index, item := range requirements {
+		path := fmt.Sprintf("/security/%d", index)
+		requirement, ok := item.(map[string]interface{})
+		if !ok {
+			c.warn(path, "dropped malformed security requirement")
+			continue
+		}
+		for _, name := range sortedKeys(requirement) {
+			if _, ok := schemes[name]; !ok {
+				c.warn(fmt.Sprintf("%s/%s", path, escapePointer(name)), "dropped reference to undefined security scheme")
+				delete(requirement, name)
+			}
+		}
+		if len(requirement) == 0 || containsRequirement(security, requirement) {
+			continue
+		}
+		security = append(security, requirement)
+	}
+
+	if servers, _ := c.data["servers"].([]interface{}); len(servers) == 0 && len(security) > 0 {
+		c.warn("/security", "moved to %s, because the document does not define servers", securityExtension)
+		c.data[securityExtension] = security
+	}
+	c.data["security"] = security
+	return nil
+}
+
+func containsRequirement(requirements []interface{}, requirement map[string]interface{}) bool {
+	for _, item := range requirements {
+		if reflect.DeepEqual(item, requirement) {
+			return true
+		}
+	}
+	return false
+}
+
+// serverSecurity returns a copy of the top-level security requirements, so servers do not share them.
+func (c *converter) serverSecurity() []interface{} {
+	requirements, _ := c.data["security"].([]interface{})
+	security := make([]interface{}, 0, len(requirements))
+	for _, item := range requirements {
+		requirement := make(map[string]interface{})
+		for name, scopes := range item.(map[string]interface{}) {
+			requirement[name] = scopes
+		}
+		security = append(security, requirement)
+	}
+	return security
+}
+
+// escapePointer escapes the JSON pointer reference token.
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,183 @@
+package v2
+
+import (
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestUpdateSecuritySchemes(t *testing.T) {
+	tests := []struct {
+		name             string
+		scheme           interface{}
+		expected         interface{}
+		expectedWarnings int
+	}{
+		{
+			name:     "supported type",
+			scheme:   map[string]interface{}{"type": "apiKey", "in": "user"},
+			expected: map[string]interface{}{"type": "apiKey", "in": "user"},
+		},
+		{
+			name:     "type case",
+			scheme:   map[string]interface{}{"type": "x509"},
+			expected: map[string]interface{}{"type": "X509"},
+		},
+		{
+			name:     "apiKey in header",
+			scheme:   map[string]interface{}{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			expected: map[string]interface{}{"type": "httpApiKey", "in": "header", "name": "X-API-Key"},
+		},
+		{
+			name:     "basic",
+			scheme:   map[string]interface{}{"type": "basic"},
+			expected: map[string]interface{}{"type": "http", "scheme": "basic"},
+		},
+		{
+			name:     "reference",
+			scheme:   map[string]interface{}{"$ref": "schemes.json#/apiKey"},
+			expected: map[string]interface{}{"$ref": "schemes.json#/apiKey"},
+		},
+		{
+			name:             "unsupported type",
+			scheme:           map[string]interface{}{"type": "kerberos"},
+			expected:         map[string]interface{}{"type": "kerberos"},
+			expectedWarnings: 1,
+		},
+		{
+			name:             "malformed",
+			scheme:           "apiKey",
+			expectedWarnings: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var warnings []Warning
+			c := converter{
+				warningHandler: func(warning Warning) {
+					warnings = append(warnings, warning)
+				},
+				data: map[string]interface{}{
+					"components": map[string]interface{}{
+						"securitySchemes": map[string]interface{}{
+							"test": test.scheme,
+						},
+					},
+				},
+			}
+			g.Expect(c.updateSecuritySchemes()).To(Succeed())
+			schemes := c.data["components"].(map[string]interface{})["securitySchemes"]
+			if test.expected == nil {
+				g.Expect(schemes).ShouldNot(HaveKey("test"))
+			} else {
+				g.Expect(schemes).To(HaveKeyWithValue("test", test.expected))
+			}
+			g.Expect(warnings).To(HaveLen(test.expectedWarnings))
+		})
+	}
+}
+
+func TestUpdateSecurity(t *testing.T) {
+	tests := []struct {
+		name             string
+		data             map[string]interface{}
+		expected         map[string]interface{}
+		expectedWarnings []string
+	}{
+		{
+			name: "servers",
+			data: map[string]interface{}{
+				"servers": []interface{}{
+					map[string]interface{}{"url": "api.example.com"},
+					map[string]interface{}{"url": "api.example.org", "security": []interface{}{}},
+				},
+				"security": []interface{}{
+					map[string]interface{}{"apiKey": []interface{}{}},
+				},
+			},
+			expected: map[string]interface{}{
+				"default": HaveKeyWithValue("security", []interface{}{
+					map[string]interface{}{"apiKey": []interface{}{}},
+				}),
+				"server1": HaveKeyWithValue("security", []interface{}{}),
+			},
+		},
+		{
+			name: "undefined and duplicated schemes",
+			data: map[string]interface{}{
+				"servers": []interface{}{
+					map[string]interface{}{"url": "api.example.com"},
+				},
+				"security": []interface{}{
+					map[string]interface{}{"apiKey": []interface{}{}, "oauth": []interface{}{"read"}},
+					map[string]interface{}{"apiKey": []interface{}{}},
+					map[string]interface{}{"token": []interface{}{}},
+				},
+			},
+			expected: map[string]interface{}{
+				"default": HaveKeyWithValue("security", []interface{}{
+					map[string]interface{}{"apiKey": []interface{}{}},
+				}),
+			},
+			expectedWarnings: []string{
+				"/security/0/oauth: dropped reference to undefined security scheme",
+				"/security/2/token: dropped reference to undefined security scheme",
+			},
+		},
+		{
+			name: "no servers",
+			data: map[string]interface{}{
+				"security": []interface{}{
+					map[string]interface{}{"apiKey": []interface{}{}},
+				},
+			},
+			expectedWarnings: []string{
+				"/security: moved to x-security, because the document does not define servers",
+			},
+		},
+		{
+			name: "empty servers",
+			data: map[string]interface{}{
+				"servers": []interface{}{},
+				"security": []interface{}{
+					map[string]interface{}{"apiKey": []interface{}{}},
+				},
+			},
+			expectedWarnings: []string{
+				"/security: moved to x-security, because the document does not define servers",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var warnings []string
+			test.data["components"] = map[string]interface{}{
+				"securitySchemes": map[string]interface{}{
+					"apiKey": map[string]interface{}{"type": "apiKey", "in": "user"},
+				},
+			}
+			c := converter{
+				warningHandler: func(warning Warning) {
+					warnings = append(warnings, warning.String())
+				},
+				data: test.data,
+			}
+			g.Expect(c.updateSecurity()).To(Succeed())
+			g.Expect(c.updateServers()).To(Succeed())
+			g.Expect(c.cleanup()).To(Succeed())
+			g.Expect(c.data).ShouldNot(HaveKey("security"))
+			if test.expected != nil {
+				for name, matcher := range test.expected {
+					g.Expect(c.data["servers"]).To(HaveKeyWithValue(name, matcher))
+				}
+			} else {
+				g.Expect(c.data).To(HaveKeyWithValue(securityExtension, []interface{}{
+					map[string]interface{}{"apiKey": []interface{}{}},
+				}))
+			}
+			g.Expect(warnings).To(ConsistOf(test.expectedWarnings))
+		})
+	}
+}
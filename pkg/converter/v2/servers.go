@@ -0,0 +1,160 @@
+package v2
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+var nonNameCharRegexp = regexp.MustCompile(`[^A-Za-z0-9_.\-]+`)
+
+// ServerNamer returns the name of the server at the index of the 1.x servers list.
+// If the returned name is empty, the server is named with DefaultServerNamer.
+// Name collisions are resolved by the converter, so the returned names do not have to be unique.
+type ServerNamer func(index int, server map[string]interface{}) (string, error)
+
+// WithServerNamer is a functional option that allows you to specify how the servers are named.
+// By default, the converter uses DefaultServerNamer.
+func WithServerNamer(namer ServerNamer) ConverterOption {
+	return func(converter *converter) error {
+		converter.serverNamer = namer
+		return nil
+	}
+}
+
+// DefaultServerNamer names the first server default and the next ones server1, server2 and so on,
+// the same way as the JavaScript AsyncAPI Converter does.
+func DefaultServerNamer(index int, _ map[string]interface{}) (string, error) {
+	//done same way as in https://github.com/asyncapi/converter/blob/020946e745342a6751565406e156c499859f5763/lib/index.js#L106
+	if index == 0 {
+		return "default", nil
+	}
+	return fmt.Sprintf("server%d", index), nil
+}
+
+// ServerNameFromHost names the server after the host of its URL, for example api.example.com.
+func ServerNameFromHost(_ int, server map[string]interface{}) (string, error) {
+	url, _ := server["url"].(string)
+	return sanitizeServerName(serverHost(url)), nil
+}
+
+// ServerNameFromExtension names the server after its x-name extension.
+func ServerNameFromExtension(_ int, server map[string]interface{}) (string, error) {
+	name, _ := server["x-name"].(string)
+	return sanitizeServerName(name), nil
+}
+
+// ServerNameFromDescription names the server after its description, for example
+// the "Production server" description results in the production-server name.
+func ServerNameFromDescription(_ int, server map[string]interface{}) (string, error) {
+	description, _ := server["description"].(string)
+	return strings.ToLower(sanitizeServerName(description)), nil
+}
+
+// ServerNamerByName returns the built-in ServerNamer with the name: default, host, x-name or description.
+func ServerNamerByName(name string) (ServerNamer, bool) {
+	switch name {
+	case "default":
+		return DefaultServerNamer, true
+	case "host":
+		return ServerNameFromHost, true
+	case "x-name":
+		return ServerNameFromExtension, true
+	case "description":
+		return ServerNameFromDescription, true
+	default:
+		return nil, false
+	}
+}
+
+// ServerTemplateData holds the values available in the server name templates.
+type ServerTemplateData struct {
+	Index       int
+	URL         string
+	Host        string
+	Protocol    string
+	Description string
+	Server      map[string]interface{}
+}
+
+// ServerNameFromTemplate returns a ServerNamer that names servers with the text/template,
+// for example {{.Protocol}}-{{.Index}}. See ServerTemplateData for the available values.
+func ServerNameFromTemplate(text string) (ServerNamer, error) {
+	tmpl, err := template.New("server").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return func(index int, server map[string]interface{}) (string, error) {
+		url, _ := server["url"].(string)
+		protocol, _ := server["protocol"].(string)
+		description, _ := server["description"].(string)
+		var name bytes.Buffer
+		err := tmpl.Execute(&name, ServerTemplateData{
+			Index:       index,
+			URL:         url,
+			Host:        serverHost(url),
+			Protocol:    protocol,
+			Description: description,
+			Server:      server,
+		})
+		if err != nil {
+			return "", err
+		}
+		return sanitizeServerName(name.String()), nil
+	}, nil
+}
+
+// serverHost returns the host of the 1.x server URL, which often does not contain the scheme,
+// for example api.example.com:{port}/path.
+func serverHost(url string) string {
+	if index := strings.Index(url, "://"); index >= 0 {
+		url = url[index+3:]
+	}
+	if index := strings.IndexAny(url, "/?#"); index >= 0 {
+		url = url[:index]
+	}
+	if index := strings.LastIndex(url, "@"); index >= 0 {
+		url = url[index+1:]
+	}
+	if index := strings.Index(url, ":"); index >= 0 {
+		url = url[:index]
+	}
+	return url
+}
+
+func sanitizeServerName(name string) string {
+	return strings.Trim(nonNameCharRegexp.ReplaceAllString(strings.TrimSpace(name), "-"), "-")
+}
+
+// uniqueServerName returns the name if it is not used yet, otherwise it appends
+// the lowest free numeric suffix to the name, for example api-2.
+func uniqueServerName(name string, used map[string]bool) string {
+	if !used[name] {
+		return name
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", name, i)
+		if !used[candidate] {
+			return candidate
+		}
+	}
+}
+
+func (c *converter) serverName(index int, server map[string]interface{}, used map[string]bool) (string, error) {
+	namer := c.serverNamer
+	if namer == nil {
+		namer = DefaultServerNamer
+	}
+	name, err := namer(index, server)
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		name, _ = DefaultServerNamer(index, server)
+	}
+	name = uniqueServerName(name, used)
+	used[name] = true
+	return name, nil
+}
@@ -0,0 +1,136 @@
+package v2
+
+import (
+	. "github.com/onsi/gomega"
+
+	"errors"
+	"testing"
+)
+
+func testServers() []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"url":         "api.example.com:{port}",
+			"scheme":      "mqtt",
+			"description": "Production server",
+			"x-name":      "production",
+		},
+		map[string]interface{}{
+			"url":         "mqtts://api.example.com/path",
+			"scheme":      "mqtts",
+			"description": "Secure production server",
+		},
+		map[string]interface{}{
+			"url":    "{host}",
+			"scheme": "ws",
+		},
+	}
+}
+
+func TestUpdateServers_namer(t *testing.T) {
+	templateNamer, err := ServerNameFromTemplate("{{.Protocol}}-{{.Index}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		namer    ServerNamer
+		expected []string
+	}{
+		{
+			name:     "default",
+			expected: []string{"default", "server1", "server2"},
+		},
+		{
+			name:     "host",
+			namer:    ServerNameFromHost,
+			expected: []string{"api.example.com", "api.example.com-2", "host"},
+		},
+		{
+			name:     "extension",
+			namer:    ServerNameFromExtension,
+			expected: []string{"production", "server1", "server2"},
+		},
+		{
+			name:     "description",
+			namer:    ServerNameFromDescription,
+			expected: []string{"production-server", "secure-production-server", "server2"},
+		},
+		{
+			name:     "template",
+			namer:    templateNamer,
+			expected: []string{"mqtt-0", "mqtts-1", "ws-2"},
+		},
+		{
+			name: "function with collisions",
+			namer: func(index int, _ map[string]interface{}) (string, error) {
+				return "api", nil
+			},
+			expected: []string{"api", "api-2", "api-3"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := converter{
+				serverNamer: test.namer,
+				data: map[string]interface{}{
+					"servers": testServers(),
+				},
+			}
+			g.Expect(c.updateServers()).To(Succeed())
+			servers := c.data["servers"].(map[string]interface{})
+			g.Expect(servers).To(HaveLen(len(test.expected)))
+			for _, name := range test.expected {
+				g.Expect(servers).To(HaveKey(name))
+			}
+		})
+	}
+}
+
+func TestUpdateServers_namer_error(t *testing.T) {
+	g := NewWithT(t)
+	c := converter{
+		serverNamer: func(int, map[string]interface{}) (string, error) {
+			return "", errors.New("test error")
+		},
+		data: map[string]interface{}{
+			"servers": testServers(),
+		},
+	}
+	g.Expect(c.updateServers()).ShouldNot(Succeed())
+}
+
+func TestServerNameFromTemplate_error(t *testing.T) {
+	g := NewWithT(t)
+	_, err := ServerNameFromTemplate("{{.Host")
+	g.Expect(err).Should(HaveOccurred())
+}
+
+func TestServerHost(t *testing.T) {
+	tests := []struct {
+		url, expected string
+	}{
+		{"api.example.com", "api.example.com"},
+		{"api.example.com:{port}/v1", "api.example.com"},
+		{"amqps://user@api.example.com:5671", "api.example.com"},
+		{"ws://{host}/stream?x=1", "{host}"},
+	}
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(serverHost(test.url)).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestServerNamerByName(t *testing.T) {
+	g := NewWithT(t)
+	for _, name := range []string{"default", "host", "x-name", "description"} {
+		namer, ok := ServerNamerByName(name)
+		g.Expect(ok).To(BeTrue(), name)
+		g.Expect(namer).ShouldNot(BeNil(), name)
+	}
+	_, ok := ServerNamerByName("unknown")
+	g.Expect(ok).To(BeFalse())
+}
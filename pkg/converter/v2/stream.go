@@ -0,0 +1,312 @@
+package v2
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
+)
+
+const (
+	// defaultStreamChannel is the name of the channel converted from the 1.x stream or events.
+	defaultStreamChannel = "/"
+	// maxLocalRefs limits the chains of local references, so the reference cycles are not followed forever.
+	maxLocalRefs = 32
+)
+
+// StreamChannelNamer returns the name of the channel converted from the 1.x stream or events of the document.
+// It is called after the servers are converted, so the document holds the 2.0.0 servers.
+type StreamChannelNamer func(document map[string]interface{}) (string, error)
+
+// WithStreamChannelNamer is a functional option that allows you to specify the name of the channel
+// converted from the 1.x stream or events. By default, the channel is named /.
+func WithStreamChannelNamer(namer StreamChannelNamer) ConverterOption {
+	return func(converter *converter) error {
+		converter.streamChannelNamer = namer
+		return nil
+	}
+}
+
+// WithStreamDiscriminator is a functional option that splits the stream or events messages into the channels
+// named after the values of the payload property, for example the type property with the enum [hello] moves
+// the message to the /hello channel. The value is read from the const or from the single-element enum of the property.
+// The messages without the value are kept in the stream channel, as are the messages with values that are not
+// a single channel name segment, such as .. or a/b, which are reported as warnings.
+func WithStreamDiscriminator(property string) ConverterOption {
+	return func(converter *converter) error {
+		converter.streamDiscriminator = property
+		return nil
+	}
+}
+
+// StreamChannelName returns a StreamChannelNamer that names the channel with the name.
+func StreamChannelName(name string) StreamChannelNamer {
+	return func(map[string]interface{}) (string, error) {
+		return name, nil
+	}
+}
+
+// StreamChannelFromExtension returns a StreamChannelNamer that names the channel after the extension,
+// for example x-path, of the stream or events, or of the document root. The extension is removed from the document.
+// If the extension is not defined, the channel is named /.
+func StreamChannelFromExtension(extension string) StreamChannelNamer {
+	return func(document map[string]interface{}) (string, error) {
+		for _, key := range []string{"stream", "events"} {
+			if object, ok := document[key].(map[string]interface{}); ok {
+				if name, ok := object[extension].(string); ok {
+					delete(object, extension)
+					return name, nil
+				}
+			}
+		}
+		if name, ok := document[extension].(string); ok {
+			delete(document, extension)
+			return name, nil
+		}
+		return defaultStreamChannel, nil
+	}
+}
+
+// StreamChannelFromServerPath names the channel after the path of the server URLs, for example
+// https://stream.gitter.im/v1/rooms/{roomId} results in the /v1/rooms/{roomId} channel, and removes the path
+// from the server URLs. The server variables of the path, such as roomId, are moved to the channel parameters.
+// If the servers do not share the same path, the channel is named /.
+func StreamChannelFromServerPath(document map[string]interface{}) (string, error) {
+	servers, _ := document["servers"].(map[string]interface{})
+	channel := ""
+	for _, item := range servers {
+		server, _ := item.(map[string]interface{})
+		url, _ := server["url"].(string)
+		_, serverPath := splitServerPath(url)
+		if serverPath == "" || (channel != "" && serverPath != channel) {
+			return defaultStreamChannel, nil
+		}
+		channel = serverPath
+	}
+	if channel == "" {
+		return defaultStreamChannel, nil
+	}
+	for _, item := range servers {
+		server := item.(map[string]interface{})
+		server["url"], _ = splitServerPath(server["url"].(string))
+	}
+	return channel, nil
+}
+
+// ParseStreamChannelNamer returns the StreamChannelNamer described by the value: server-path for
+// StreamChannelFromServerPath, an extension name starting with x- for StreamChannelFromExtension,
+// or the channel name for StreamChannelName.
+func ParseStreamChannelNamer(value string) StreamChannelNamer {
+	switch {
+	case value == "server-path":
+		return StreamChannelFromServerPath
+	case strings.HasPrefix(value, "x-"):
+		return StreamChannelFromExtension(value)
+	default:
+		return StreamChannelName(value)
+	}
+}
+
+// splitServerPath splits the server URL into the URL without the path and the path.
+func splitServerPath(url string) (string, string) {
+	start := 0
+	if index := strings.Index(url, "://"); index >= 0 {
+		start = index + 3
+	}
+	index := strings.Index(url[start:], "/")
+	if index < 0 || start+index == len(url)-1 {
+		return url, ""
+	}
+	return url[:start+index], url[start+index:]
+}
+
+func (c *converter) channelsFromStream() error {
+	return c.streamChannels("stream", "read", "write")
+}
+
+func (c *converter) channelsFromEvents() error {
+	return c.streamChannels("events", "receive", "send")
+}
+
+// streamChannels creates the channels from the stream or events section, with the subscribe operation
+// of the received messages and the publish operation of the sent messages.
+func (c *converter) streamChannels(section, receive, send string) error {
+	stream, ok := c.data[section].(map[string]interface{})
+	if !ok {
+		return asyncapierr.NewInvalidProperty(section)
+	}
+	name := defaultStreamChannel
+	if c.streamChannelNamer != nil {
+		var err error
+		name, err = c.streamChannelNamer(c.data)
+		if err != nil {
+			return err
+		}
+	}
+	variables := c.takeServerVariables(name)
+
+	messages := make(map[string]map[string][]interface{})
+	add := func(channel, operation string, message interface{}) {
+		if messages[channel] == nil {
+			messages[channel] = make(map[string][]interface{})
+		}
+		messages[channel][operation] = append(messages[channel][operation], message)
+	}
+	for _, operation := range []struct {
+		name string
+		key  string
+	}{
+		{"subscribe", receive},
+		{"publish", send},
+	} {
+		operationMessages, _ := stream[operation.key].([]interface{})
+		for index, message := range operationMessages {
+			if err := c.checkContext(); err != nil {
+				return err
+			}
+			channel := name
+			if value, ok := c.discriminatorValue(message); ok {
+				if isChannelSegment(value) {
+					channel = path.Join(name, value)
+				} else {
+					c.warn(fmt.Sprintf("/%s/%s/%d", section, operation.key, index),
+						"kept the message in the %s channel, because the discriminator value %q is not a channel name segment", name, value)
+				}
+			}
+			add(channel, operation.name, message)
+		}
+	}
+
+	channels := make(map[string]interface{})
+	for channelName, operations := range messages {
+		channel := make(map[string]interface{})
+		for operation, operationMessages := range operations {
+			channel[operation] = newOperation(operationMessages)
+		}
+		channels[channelName] = channel
+	}
+	if len(channels) == 0 {
+		channels[name] = make(map[string]interface{})
+	}
+	for channelName, channel := range channels {
+		if parameters := channelParameters(channelName, variables); len(parameters) > 0 {
+			channel.(map[string]interface{})["parameters"] = parameters
+		}
+	}
+	c.data["channels"] = channels
+	return nil
+}
+
+// isChannelSegment reports whether the discriminator value can be appended to the stream channel name
+// as a single segment, so it cannot move the channel above the stream channel or add channel parameters.
+func isChannelSegment(value string) bool {
+	return value != "" && value != "." && value != ".." && !strings.ContainsAny(value, "/{}")
+}
+
+// takeServerVariables removes the server variables named after the parameters of the channel name,
+// for example roomId of /v1/rooms/{roomId}, from the servers whose URLs no longer refer to them,
+// and returns the variables. The variable of the first server by name is returned.
+func (c *converter) takeServerVariables(channelName string) map[string]map[string]interface{} {
+	result := make(map[string]map[string]interface{})
+	servers, _ := c.data["servers"].(map[string]interface{})
+	for _, serverName := range sortedKeys(servers) {
+		server, _ := servers[serverName].(map[string]interface{})
+		variables, _ := server["variables"].(map[string]interface{})
+		url, _ := server["url"].(string)
+		for _, parameter := range channelParameterRegexp.FindAllString(channelName, -1) {
+			name := parameter[1 : len(parameter)-1]
+			variable, ok := variables[name].(map[string]interface{})
+			if !ok || strings.Contains(url, parameter) {
+				continue
+			}
+			if _, ok := result[name]; !ok {
+				result[name] = variable
+			}
+			delete(variables, name)
+		}
+		if variables != nil && len(variables) == 0 {
+			delete(server, "variables")
+		}
+	}
+	return result
+}
+
+// channelParameters returns the parameters of the channel name converted from the server variables.
+// The description of the variable is kept, and its enum, default and examples describe the string schema.
+// The parameters without the variable are described as strings.
+func channelParameters(channelName string, variables map[string]map[string]interface{}) map[string]interface{} {
+	parameters := make(map[string]interface{})
+	for _, parameter := range channelParameterRegexp.FindAllString(channelName, -1) {
+		name := parameter[1 : len(parameter)-1]
+		schema := map[string]interface{}{"type": "string"}
+		result := map[string]interface{}{"schema": schema}
+		for key, value := range variables[name] {
+			if key == "description" {
+				result[key] = value
+			} else {
+				schema[key] = value
+			}
+		}
+		parameters[name] = result
+	}
+	return parameters
+}
+
+// discriminatorValue returns the value of the discriminator property of the message payload.
+func (c *converter) discriminatorValue(message interface{}) (string, bool) {
+	if c.streamDiscriminator == "" {
+		return "", false
+	}
+	messageMap, ok := c.resolveLocalRef(message).(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	payload, ok := c.resolveLocalRef(messageMap["payload"]).(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	properties, _ := payload["properties"].(map[string]interface{})
+	property, ok := c.resolveLocalRef(properties[c.streamDiscriminator]).(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	if value, ok := property["const"]; ok {
+		return fmt.Sprintf("%v", value), true
+	}
+	if enum, ok := property["enum"].([]interface{}); ok && len(enum) == 1 {
+		return fmt.Sprintf("%v", enum[0]), true
+	}
+	return "", false
+}
+
+// resolveLocalRef returns the value the local reference, for example #/components/messages/hello, points to.
+// Other values are returned as they are.
+func (c *converter) resolveLocalRef(value interface{}) interface{} {
+	for i := 0; i < maxLocalRefs; i++ {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		ref, ok := object["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return value
+		}
+		value = c.lookupPointer(ref)
+	}
+	return nil
+}
+
+// lookupPointer returns the value of the document the local reference points to, or nil if it does not exist.
+func (c *converter) lookupPointer(ref string) interface{} {
+	var current interface{} = c.data
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		currentMap, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = currentMap[token]
+	}
+	return current
+}
@@ -0,0 +1,265 @@
+package v2
+
+import (
+	"github.com/asyncapi/converter-go/pkg/decode"
+	"github.com/asyncapi/converter-go/pkg/encode"
+	. "github.com/onsi/gomega"
+
+	"encoding/json"
+	"testing"
+)
+
+func testStreamMessage(value string) map[string]interface{} {
+	return map[string]interface{}{
+		"payload": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"type": map[string]interface{}{"type": "string", "enum": []interface{}{value}},
+			},
+		},
+	}
+}
+
+func TestStreamChannels(t *testing.T) {
+	tests := []struct {
+		name          string
+		namer         StreamChannelNamer
+		discriminator string
+		data          map[string]interface{}
+		expected      map[string]interface{}
+		expectedURL   string
+	}{
+		{
+			name: "default",
+			data: map[string]interface{}{
+				"stream": map[string]interface{}{
+					"read": []interface{}{testStreamMessage("a")},
+				},
+			},
+			expected: map[string]interface{}{
+				"/": HaveKey("subscribe"),
+			},
+		},
+		{
+			name: "empty stream",
+			data: map[string]interface{}{
+				"stream": map[string]interface{}{},
+			},
+			expected: map[string]interface{}{
+				"/": BeEmpty(),
+			},
+		},
+		{
+			name:  "name",
+			namer: StreamChannelName("/rtm"),
+			data: map[string]interface{}{
+				"events": map[string]interface{}{
+					"send": []interface{}{testStreamMessage("a")},
+				},
+			},
+			expected: map[string]interface{}{
+				"/rtm": HaveKey("publish"),
+			},
+		},
+		{
+			name:  "extension",
+			namer: StreamChannelFromExtension("x-path"),
+			data: map[string]interface{}{
+				"events": map[string]interface{}{
+					"x-path":  "/websocket",
+					"receive": []interface{}{testStreamMessage("a")},
+				},
+			},
+			expected: map[string]interface{}{
+				"/websocket": HaveKey("subscribe"),
+			},
+		},
+		{
+			name:  "server path",
+			namer: StreamChannelFromServerPath,
+			data: map[string]interface{}{
+				"servers": []interface{}{
+					map[string]interface{}{"url": "https://stream.gitter.im/v1/rooms/{roomId}", "scheme": "https"},
+				},
+				"stream": map[string]interface{}{
+					"read": []interface{}{testStreamMessage("a")},
+				},
+			},
+			expected: map[string]interface{}{
+				"/v1/rooms/{roomId}": HaveKey("subscribe"),
+			},
+			expectedURL: "https://stream.gitter.im",
+		},
+		{
+			name:  "different server paths",
+			namer: StreamChannelFromServerPath,
+			data: map[string]interface{}{
+				"servers": []interface{}{
+					map[string]interface{}{"url": "https://example.com/v1", "scheme": "https"},
+					map[string]interface{}{"url": "https://example.com/v2", "scheme": "https"},
+				},
+				"stream": map[string]interface{}{
+					"read": []interface{}{testStreamMessage("a")},
+				},
+			},
+			expected: map[string]interface{}{
+				"/": HaveKey("subscribe"),
+			},
+			expectedURL: "https://example.com/v1",
+		},
+		{
+			name:          "discriminator",
+			namer:         StreamChannelName("/rtm"),
+			discriminator: "type",
+			data: map[string]interface{}{
+				"components": map[string]interface{}{
+					"messages": map[string]interface{}{
+						"hello": testStreamMessage("hello"),
+					},
+				},
+				"events": map[string]interface{}{
+					"receive": []interface{}{
+						map[string]interface{}{"$ref": "#/components/messages/hello"},
+						testStreamMessage("message"),
+						map[string]interface{}{"payload": map[string]interface{}{"type": "string"}},
+						map[string]interface{}{"$ref": "#/components/messages/missing"},
+					},
+					"send": []interface{}{testStreamMessage("message")},
+				},
+			},
+			expected: map[string]interface{}{
+				"/rtm/hello": Equal(map[string]interface{}{
+					"subscribe": map[string]interface{}{
+						"message": map[string]interface{}{"$ref": "#/components/messages/hello"},
+					},
+				}),
+				"/rtm/message": And(HaveKey("subscribe"), HaveKey("publish")),
+				"/rtm":         HaveKeyWithValue("subscribe", HaveKeyWithValue("message", HaveKeyWithValue("oneOf", HaveLen(2)))),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := converter{
+				streamChannelNamer:  test.namer,
+				streamDiscriminator: test.discriminator,
+				data:                test.data,
+			}
+			g.Expect(c.updateServers()).To(Succeed())
+			g.Expect(c.createChannels()).To(Succeed())
+			channels := c.data["channels"].(map[string]interface{})
+			g.Expect(channels).To(HaveLen(len(test.expected)))
+			for name, matcher := range test.expected {
+				g.Expect(channels).To(HaveKeyWithValue(name, matcher))
+			}
+			if test.expectedURL != "" {
+				g.Expect(c.data["servers"]).To(HaveKeyWithValue("default", HaveKeyWithValue("url", test.expectedURL)))
+			}
+		})
+	}
+}
+
+func TestStreamChannels_invalid_discriminator_values(t *testing.T) {
+	g := NewWithT(t)
+	var warnings []string
+	c := converter{
+		streamChannelNamer:  StreamChannelName("/rtm"),
+		streamDiscriminator: "type",
+		warningHandler: func(warning Warning) {
+			warnings = append(warnings, warning.String())
+		},
+		data: map[string]interface{}{
+			"events": map[string]interface{}{
+				"receive": []interface{}{
+					testStreamMessage(".."),
+					testStreamMessage("a/b"),
+					testStreamMessage("{id}"),
+					testStreamMessage(""),
+					testStreamMessage("hello"),
+				},
+			},
+		},
+	}
+	g.Expect(c.createChannels()).To(Succeed())
+	channels := c.data["channels"].(map[string]interface{})
+	g.Expect(channels).To(HaveLen(2))
+	g.Expect(channels).To(HaveKeyWithValue("/rtm", HaveKeyWithValue("subscribe", HaveKeyWithValue("message", HaveKeyWithValue("oneOf", HaveLen(4))))))
+	g.Expect(channels).To(HaveKey("/rtm/hello"))
+	g.Expect(warnings).To(Equal([]string{
+		`/events/receive/0: kept the message in the /rtm channel, because the discriminator value ".." is not a channel name segment`,
+		`/events/receive/1: kept the message in the /rtm channel, because the discriminator value "a/b" is not a channel name segment`,
+		`/events/receive/2: kept the message in the /rtm channel, because the discriminator value "{id}" is not a channel name segment`,
+		`/events/receive/3: kept the message in the /rtm channel, because the discriminator value "" is not a channel name segment`,
+	}))
+}
+
+func TestStreamChannels_server_variables(t *testing.T) {
+	g := NewWithT(t)
+	c := converter{
+		streamChannelNamer:  StreamChannelFromServerPath,
+		streamDiscriminator: "type",
+		data: map[string]interface{}{
+			"servers": []interface{}{
+				map[string]interface{}{
+					"url":    "wss://{host}/rooms/{roomId}",
+					"scheme": "wss",
+					"variables": map[string]interface{}{
+						"host":   map[string]interface{}{"default": "example.com"},
+						"roomId": map[string]interface{}{"description": "Id of the room.", "default": "lobby"},
+					},
+				},
+			},
+			"stream": map[string]interface{}{
+				"read": []interface{}{testStreamMessage("hello"), map[string]interface{}{}},
+			},
+		},
+	}
+	g.Expect(c.updateServers()).To(Succeed())
+	g.Expect(c.createChannels()).To(Succeed())
+	g.Expect(c.data["servers"]).To(HaveKeyWithValue("default", map[string]interface{}{
+		"url":       "wss://{host}",
+		"protocol":  "wss",
+		"variables": map[string]interface{}{"host": map[string]interface{}{"default": "example.com"}},
+	}))
+	parameters := map[string]interface{}{
+		"roomId": map[string]interface{}{
+			"description": "Id of the room.",
+			"schema":      map[string]interface{}{"type": "string", "default": "lobby"},
+		},
+	}
+	channels := c.data["channels"].(map[string]interface{})
+	g.Expect(channels).To(HaveKeyWithValue("/rooms/{roomId}", HaveKeyWithValue("parameters", parameters)))
+	g.Expect(channels).To(HaveKeyWithValue("/rooms/{roomId}/hello", HaveKeyWithValue("parameters", parameters)))
+}
+
+func TestStreamChannels_slack(t *testing.T) {
+	g := NewWithT(t)
+	converter, err := New(decode.FromJSON, encode.ToJSON,
+		WithStreamChannelNamer(ParseStreamChannelNamer("server-path")),
+		WithStreamDiscriminator("type"),
+	)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	result := convertFile(converter, "./testdata/input/slack-rtm1.2.0.json", g)
+
+	var document map[string]interface{}
+	g.Expect(json.Unmarshal([]byte(result), &document)).To(Succeed())
+	g.Expect(document["servers"]).To(HaveKeyWithValue("default", HaveKeyWithValue("url", "https://slack.com")))
+	channels := document["channels"].(map[string]interface{})
+	g.Expect(channels).To(HaveKeyWithValue("/api/rtm.connect/hello", HaveKey("subscribe")))
+	g.Expect(channels).To(HaveKeyWithValue("/api/rtm.connect/message", And(HaveKey("subscribe"), HaveKey("publish"))))
+	g.Expect(channels).ShouldNot(HaveKey("/"))
+}
+
+func TestParseStreamChannelNamer(t *testing.T) {
+	g := NewWithT(t)
+	document := map[string]interface{}{"x-path": "/ws"}
+	name, err := ParseStreamChannelNamer("x-path")(document)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(name).To(Equal("/ws"))
+	g.Expect(document).ShouldNot(HaveKey("x-path"))
+
+	name, err = ParseStreamChannelNamer("/rtm")(document)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(name).To(Equal("/rtm"))
+}
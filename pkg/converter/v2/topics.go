@@ -0,0 +1,160 @@
+package v2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
+)
+
+// deprecatedExtension holds the 1.x deprecated flag of topics and messages,
+// as AsyncAPI 2.0.0 does not define it for channels and messages.
+const deprecatedExtension = "x-deprecated"
+
+// topicOperations are the 1.x topic operations converted to the 2.0.0 channel operations.
+var topicOperations = []string{"publish", "subscribe"}
+
+func (c *converter) channelsFromTopics() error {
+	channels := make(map[string]interface{})
+	topics, ok := c.data["topics"].(map[string]interface{})
+	if !ok {
+		return asyncapierr.NewInvalidProperty("topics")
+	}
+	var baseTopic string
+	if value, ok := c.data["baseTopic"]; ok && value != nil {
+		baseTopic = fmt.Sprintf("%v", value)
+	}
+	mappedTopics := make(map[string]string)
+	for _, key := range sortedKeys(topics) {
+		if err := c.checkContext(); err != nil {
+			return err
+		}
+		channelKey, err := c.channelName(baseTopic, key)
+		if err != nil {
+			return err
+		}
+		if other, ok := mappedTopics[channelKey]; ok {
+			return asyncapierr.NewInvalidProperty(fmt.Sprintf("topics %s and %s map to the same channel %s", other, key, channelKey))
+		}
+		mappedTopics[channelKey] = key
+
+		topic, ok := topics[key].(map[string]interface{})
+		if !ok {
+			return asyncapierr.NewInvalidProperty("malformed topic")
+		}
+		channel := topicToChannel(topic)
+		// the parameters are named after the topic, as the channel name mapper may drop them from the channel name
+		if params, ok := channel["parameters"].([]interface{}); ok {
+			alteredParameters, err := alterParameters(params, key)
+			if err != nil {
+				return err
+			}
+			c.dropUnusedParameters(channelKey, alteredParameters)
+			if len(alteredParameters) > 0 {
+				channel["parameters"] = alteredParameters
+			} else {
+				delete(channel, "parameters")
+			}
+		}
+		channels[channelKey] = channel
+	}
+	c.data["channels"] = channels
+	return nil
+}
+
+// dropUnusedParameters removes the parameters the channel name does not refer to, for example the parameters
+// replaced with the wildcards by MQTTWildcardChannelNames, as 2.0.0 defines the parameters of the channel name only.
+func (c *converter) dropUnusedParameters(channelKey string, parameters map[string]interface{}) {
+	for _, name := range sortedKeys(parameters) {
+		if !strings.Contains(channelKey, "{"+name+"}") {
+			delete(parameters, name)
+			c.warn(fmt.Sprintf("/channels/%s/parameters/%s", escapePointer(channelKey), escapePointer(name)),
+				"dropped parameter, because the channel name does not refer to it")
+		}
+	}
+}
+
+func sortedKeys(items map[string]interface{}) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// topicToChannel converts the 1.x topic into the 2.0.0 channel:
+//
+//   - the publish and subscribe messages are wrapped into the operations,
+//   - the arrays of messages are converted into the oneOf messages,
+//   - the deprecated flags of the topic and its messages are moved to the x-deprecated extension.
+//
+// The remaining properties, such as parameters and extensions, are copied.
+func topicToChannel(topic map[string]interface{}) map[string]interface{} {
+	channel := make(map[string]interface{})
+	for key, value := range topic {
+		channel[key] = value
+	}
+	for _, operation := range topicOperations {
+		if topic[operation] == nil {
+			delete(channel, operation)
+			continue
+		}
+		channel[operation] = newOperation(topic[operation])
+	}
+	if deprecated, ok := channel["deprecated"]; ok {
+		delete(channel, "deprecated")
+		if deprecated == true {
+			channel[deprecatedExtension] = true
+		}
+	}
+	return channel
+}
+
+// newOperation returns the operation with the message. The message is either a single message,
+// the oneOf message or an array of messages converted into the oneOf message.
+func newOperation(message interface{}) map[string]interface{} {
+	if messages, ok := message.([]interface{}); ok {
+		if len(messages) == 1 {
+			message = messages[0]
+		} else {
+			message = map[string]interface{}{
+				"oneOf": messages,
+			}
+		}
+	}
+	forEachMessage(message, deprecateMessage)
+	return map[string]interface{}{
+		"message": message,
+	}
+}
+
+// forEachMessage calls fn for the message or for every message of the oneOf message.
+func forEachMessage(message interface{}, fn func(map[string]interface{})) {
+	item, ok := message.(map[string]interface{})
+	if !ok {
+		return
+	}
+	oneOf, ok := item["oneOf"].([]interface{})
+	if !ok {
+		fn(item)
+		return
+	}
+	for _, element := range oneOf {
+		if elementMap, ok := element.(map[string]interface{}); ok {
+			fn(elementMap)
+		}
+	}
+}
+
+func deprecateMessage(message map[string]interface{}) {
+	deprecated, ok := message["deprecated"]
+	if !ok {
+		return
+	}
+	delete(message, "deprecated")
+	if deprecated == true {
+		message[deprecatedExtension] = true
+	}
+}
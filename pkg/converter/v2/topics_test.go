@@ -0,0 +1,175 @@
+package v2
+
+import (
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestChannelsFromTopics(t *testing.T) {
+	message := func(summary string) map[string]interface{} {
+		return map[string]interface{}{
+			"summary": summary,
+			"payload": map[string]interface{}{"type": "string"},
+		}
+	}
+	tests := []struct {
+		name     string
+		topic    map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name:  "publish",
+			topic: map[string]interface{}{"publish": message("a")},
+			expected: map[string]interface{}{
+				"publish": map[string]interface{}{"message": message("a")},
+			},
+		},
+		{
+			name:  "subscribe",
+			topic: map[string]interface{}{"subscribe": message("a")},
+			expected: map[string]interface{}{
+				"subscribe": map[string]interface{}{"message": message("a")},
+			},
+		},
+		{
+			name:  "publish and subscribe",
+			topic: map[string]interface{}{"publish": message("a"), "subscribe": message("b")},
+			expected: map[string]interface{}{
+				"publish":   map[string]interface{}{"message": message("a")},
+				"subscribe": map[string]interface{}{"message": message("b")},
+			},
+		},
+		{
+			name: "oneOf",
+			topic: map[string]interface{}{
+				"publish": map[string]interface{}{"oneOf": []interface{}{message("a"), message("b")}},
+			},
+			expected: map[string]interface{}{
+				"publish": map[string]interface{}{
+					"message": map[string]interface{}{"oneOf": []interface{}{message("a"), message("b")}},
+				},
+			},
+		},
+		{
+			name:  "array of messages",
+			topic: map[string]interface{}{"subscribe": []interface{}{message("a"), message("b")}},
+			expected: map[string]interface{}{
+				"subscribe": map[string]interface{}{
+					"message": map[string]interface{}{"oneOf": []interface{}{message("a"), message("b")}},
+				},
+			},
+		},
+		{
+			name:  "array of one message",
+			topic: map[string]interface{}{"subscribe": []interface{}{message("a")}},
+			expected: map[string]interface{}{
+				"subscribe": map[string]interface{}{"message": message("a")},
+			},
+		},
+		{
+			name:  "null operation",
+			topic: map[string]interface{}{"publish": nil, "subscribe": message("a")},
+			expected: map[string]interface{}{
+				"subscribe": map[string]interface{}{"message": message("a")},
+			},
+		},
+		{
+			name:  "deprecated topic",
+			topic: map[string]interface{}{"deprecated": true, "publish": message("a")},
+			expected: map[string]interface{}{
+				"x-deprecated": true,
+				"publish":      map[string]interface{}{"message": message("a")},
+			},
+		},
+		{
+			name:  "not deprecated topic",
+			topic: map[string]interface{}{"deprecated": false, "publish": message("a")},
+			expected: map[string]interface{}{
+				"publish": map[string]interface{}{"message": message("a")},
+			},
+		},
+		{
+			name: "deprecated messages",
+			topic: map[string]interface{}{
+				"publish":   map[string]interface{}{"deprecated": true, "summary": "a"},
+				"subscribe": map[string]interface{}{"oneOf": []interface{}{map[string]interface{}{"deprecated": true}}},
+			},
+			expected: map[string]interface{}{
+				"publish": map[string]interface{}{
+					"message": map[string]interface{}{"x-deprecated": true, "summary": "a"},
+				},
+				"subscribe": map[string]interface{}{
+					"message": map[string]interface{}{"oneOf": []interface{}{map[string]interface{}{"x-deprecated": true}}},
+				},
+			},
+		},
+		{
+			name: "headers in oneOf",
+			topic: map[string]interface{}{
+				"publish": []interface{}{
+					map[string]interface{}{"headers": map[string]interface{}{"id": map[string]interface{}{"type": "string"}}},
+					message("b"),
+				},
+			},
+			expected: map[string]interface{}{
+				"publish": map[string]interface{}{
+					"message": map[string]interface{}{"oneOf": []interface{}{
+						map[string]interface{}{"headers": map[string]interface{}{
+							"type":       "object",
+							"properties": map[string]interface{}{"id": map[string]interface{}{"type": "string"}},
+						}},
+						message("b"),
+					}},
+				},
+			},
+		},
+		{
+			name: "parameters and extensions",
+			topic: map[string]interface{}{
+				"parameters": []interface{}{map[string]interface{}{"name": "id"}},
+				"x-custom":   "value",
+				"publish":    message("a"),
+			},
+			expected: map[string]interface{}{
+				"parameters": map[string]interface{}{"id": map[string]interface{}{}},
+				"x-custom":   "value",
+				"publish":    map[string]interface{}{"message": message("a")},
+			},
+		},
+		{
+			name:     "reference",
+			topic:    map[string]interface{}{"$ref": "topics.json#/test"},
+			expected: map[string]interface{}{"$ref": "topics.json#/test"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := converter{
+				data: map[string]interface{}{
+					"topics": map[string]interface{}{
+						"test.{id}": test.topic,
+					},
+				},
+			}
+			g.Expect(c.createChannels()).To(Succeed())
+			g.Expect(c.alterChannels()).To(Succeed())
+			g.Expect(c.data["channels"]).To(Equal(map[string]interface{}{
+				"test/{id}": test.expected,
+			}))
+		})
+	}
+}
+
+func TestChannelsFromTopics_malformed(t *testing.T) {
+	g := NewWithT(t)
+	c := converter{
+		data: map[string]interface{}{
+			"topics": map[string]interface{}{
+				"test": "publish",
+			},
+		},
+	}
+	g.Expect(c.createChannels()).ShouldNot(Succeed())
+}
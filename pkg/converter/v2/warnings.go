@@ -0,0 +1,40 @@
+package v2
+
+import (
+	"fmt"
+)
+
+// Warning describes a part of the document that the converter dropped or changed
+// in a way that may require attention.
+type Warning struct {
+	// Path is the JSON pointer of the affected property in the converted document, for example /security/0/apiKey.
+	Path string
+	// Message describes what happened to the property.
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Path, w.Message)
+}
+
+// WarningHandler is called by the converter for every Warning reported during the conversion.
+type WarningHandler func(Warning)
+
+// WithWarningHandler is a functional option that allows you to receive the warnings reported during the conversion.
+// By default, the warnings are discarded.
+func WithWarningHandler(handler WarningHandler) ConverterOption {
+	return func(converter *converter) error {
+		converter.warningHandler = handler
+		return nil
+	}
+}
+
+func (c *converter) warn(path, format string, args ...interface{}) {
+	if c.warningHandler == nil {
+		return
+	}
+	c.warningHandler(Warning{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
@@ -0,0 +1,150 @@
+package decode
+
+import (
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
+
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Format is the format of an AsyncAPI document.
+type Format string
+
+const (
+	// FormatJSON is the JSON format.
+	FormatJSON Format = "json"
+	// FormatYAML is the YAML format.
+	FormatYAML Format = "yaml"
+)
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// Error is returned when a document is neither a valid JSON nor a valid YAML document.
+// It holds the errors of both parsers.
+type Error struct {
+	JSON error
+	YAML error
+}
+
+func (err Error) Error() string {
+	yamlMsg := strings.TrimPrefix(err.YAML.Error(), "yaml: ")
+	return fmt.Sprintf("json: %s; yaml: %s", err.JSON, yamlMsg)
+}
+
+// Auto decodes AsyncAPI documents in the JSON or the YAML format. The format is taken from the media type
+// or the file extension, if they are known, otherwise it is detected from the content. If the document cannot
+// be decoded in the expected format, the other format is tried, and if both fail, Decode returns the Error
+// holding both parser messages.
+//
+// The Decode method can be passed to the converter as the Decode function:
+//
+//	auto := &decode.Auto{Path: path}
+//	converter, err := v2.New(auto.Decode, encode.ToJSON)
+type Auto struct {
+	// Path is the file path or URL of the decoded document. The .json, .yaml and .yml extensions select the format.
+	Path string
+	// MediaType is the media type of the decoded document, for example application/json.
+	// It takes precedence over the Path.
+	MediaType string
+	// Strict enables the strict YAML decoding, see FromYamlStrict.
+	Strict bool
+	// Limits bound the decoded document.
+	Limits Limits
+	// Format is the format of the last document decoded successfully.
+	Format Format
+}
+
+// Decode reads an AsyncAPI document from input and stores it in the value.
+// The detected format is stored in the Format field.
+func (a *Auto) Decode(v interface{}, reader io.Reader) error {
+	data, err := a.Limits.read(reader)
+	if err != nil {
+		return err
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+	format := a.hint()
+	if format == "" {
+		format = Sniff(data)
+	}
+	unmarshalers := map[Format]unmarshalFunc{
+		FormatJSON: a.Limits.unmarshalJSON,
+		FormatYAML: func(in []byte, out interface{}) error {
+			return a.Limits.unmarshalYaml(in, out, a.Strict)
+		},
+	}
+	errs := make(map[Format]error, len(unmarshalers))
+	for _, f := range []Format{format, otherFormat(format)} {
+		errs[f] = unmarshalers[f](data, v)
+		if errs[f] == nil {
+			a.Format = f
+			return nil
+		}
+		if asyncapierr.IsLimitExceeded(errs[f]) {
+			return errs[f]
+		}
+	}
+	return Error{JSON: errs[FormatJSON], YAML: errs[FormatYAML]}
+}
+
+func (a *Auto) hint() Format {
+	if format := FormatFromMediaType(a.MediaType); format != "" {
+		return format
+	}
+	return FormatFromPath(a.Path)
+}
+
+func otherFormat(format Format) Format {
+	if format == FormatJSON {
+		return FormatYAML
+	}
+	return FormatJSON
+}
+
+// Sniff detects the format of the document from its content. Documents starting with { or [
+// are JSON documents, any other documents are YAML documents.
+func Sniff(data []byte) Format {
+	data = bytes.TrimLeft(bytes.TrimPrefix(data, utf8BOM), " \t\r\n")
+	if len(data) > 0 && (data[0] == '{' || data[0] == '[') {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
+// FormatFromPath returns the format matching the extension of the file path or URL,
+// or an empty format if the extension is unknown.
+func FormatFromPath(p string) Format {
+	if u, err := url.Parse(p); err == nil && u.Scheme != "" && u.Host != "" {
+		p = u.Path
+	}
+	switch strings.ToLower(path.Ext(p)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	default:
+		return ""
+	}
+}
+
+// FormatFromMediaType returns the format matching the media type, for example application/json
+// or application/x-yaml, or an empty format if the media type is unknown.
+func FormatFromMediaType(mediaType string) Format {
+	mediaType, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return FormatJSON
+	case strings.HasSuffix(mediaType, "/yaml") || strings.HasSuffix(mediaType, "/x-yaml") ||
+		strings.HasSuffix(mediaType, "+yaml"):
+		return FormatYAML
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,86 @@
+package decode
+
+import (
+	. "github.com/onsi/gomega"
+
+	"strings"
+	"testing"
+)
+
+func TestAuto_Decode(t *testing.T) {
+	tests := []struct {
+		name           string
+		auto           Auto
+		data           string
+		expectedFormat Format
+	}{
+		{
+			name:           "sniffed json",
+			data:           "\xef\xbb\xbf\n  {\"test\": \"me\"}",
+			expectedFormat: FormatJSON,
+		},
+		{
+			name:           "sniffed yaml",
+			data:           "test: me",
+			expectedFormat: FormatYAML,
+		},
+		{
+			name:           "yaml flow mapping",
+			data:           "{test: me}",
+			expectedFormat: FormatYAML,
+		},
+		{
+			name:           "path extension",
+			auto:           Auto{Path: "https://example.com/asyncapi.YML?ref=main"},
+			data:           `{"test": "me"}`,
+			expectedFormat: FormatYAML,
+		},
+		{
+			name:           "media type takes precedence",
+			auto:           Auto{Path: "asyncapi.yaml", MediaType: "application/vnd.aai.asyncapi+json; version=1.2.0"},
+			data:           `{"test": "me"}`,
+			expectedFormat: FormatJSON,
+		},
+		{
+			name:           "fallback to the other format",
+			auto:           Auto{Path: "asyncapi.json"},
+			data:           "test: me",
+			expectedFormat: FormatYAML,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var out interface{}
+			g.Expect(test.auto.Decode(&out, strings.NewReader(test.data))).To(Succeed())
+			g.Expect(out).To(Equal(map[string]interface{}{"test": "me"}))
+			g.Expect(test.auto.Format).To(Equal(test.expectedFormat))
+		})
+	}
+}
+
+func TestAuto_Decode_error(t *testing.T) {
+	g := NewWithT(t)
+	auto := Auto{}
+	var out interface{}
+	err := auto.Decode(&out, strings.NewReader("{\"test\": x"))
+	g.Expect(err).To(BeAssignableToTypeOf(Error{}))
+	g.Expect(err.(Error).JSON).Should(HaveOccurred())
+	g.Expect(err.(Error).YAML).Should(HaveOccurred())
+	g.Expect(err.Error()).To(And(
+		HavePrefix("json: invalid character 'x'"),
+		ContainSubstring("; yaml: "),
+	))
+	g.Expect(auto.Format).To(BeEmpty())
+
+	g.Expect(auto.Decode(&out, errNoProgressReader)).Should(HaveOccurred())
+}
+
+func TestFormatFromMediaType(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(FormatFromMediaType("application/json; charset=utf-8")).To(Equal(FormatJSON))
+	g.Expect(FormatFromMediaType("application/x-yaml")).To(Equal(FormatYAML))
+	g.Expect(FormatFromMediaType("text/yaml")).To(Equal(FormatYAML))
+	g.Expect(FormatFromMediaType("text/plain")).To(BeEmpty())
+	g.Expect(FormatFromMediaType("")).To(BeEmpty())
+}
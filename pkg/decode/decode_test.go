@@ -56,11 +56,60 @@ func TestUnmarshalYamlReaderError2(t *testing.T) {
 	var out interface{}
 	err := FromJSONWithYamlFallback(&out, reader)
 	g.Expect(err).Should(HaveOccurred())
+	g.Expect(err.Error()).To(HavePrefix("json: "))
 }
 
 func TestUnmarshalYaml_err(t *testing.T) {
 	g := NewWithT(t)
 	var out interface{}
-	err := unmarshalYaml([]byte(","), &out)
+	err := Limits{}.unmarshalYaml([]byte(","), &out, false)
 	g.Expect(err).Should(HaveOccurred())
 }
+
+func TestAliases(t *testing.T) {
+	data := "headers: &headers {type: object}\nfirst:\n  headers: *headers\nsecond:\n  <<: {headers: *headers}\n"
+	for name, decode := range map[string]func(interface{}, io.Reader) error{
+		"FromYaml":       FromYaml,
+		"FromYamlStrict": FromYamlStrict,
+	} {
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			var out interface{}
+			g.Expect(decode(&out, strings.NewReader(data))).To(Succeed())
+			document := out.(map[string]interface{})
+			first := document["first"].(map[string]interface{})["headers"].(map[string]interface{})
+			first["type"] = "string"
+			g.Expect(document["headers"]).To(HaveKeyWithValue("type", "object"))
+			g.Expect(document["second"]).To(HaveKeyWithValue("headers", HaveKeyWithValue("type", "object")))
+		})
+	}
+}
+
+func TestAliases_excessive(t *testing.T) {
+	data := `a: &a [x, x, x, x, x, x, x, x, x]
+b: &b [*a, *a, *a, *a, *a, *a, *a, *a, *a]
+c: &c [*b, *b, *b, *b, *b, *b, *b, *b, *b]
+d: &d [*c, *c, *c, *c, *c, *c, *c, *c, *c]
+e: &e [*d, *d, *d, *d, *d, *d, *d, *d, *d]
+f: &f [*e, *e, *e, *e, *e, *e, *e, *e, *e]
+`
+	for name, decode := range map[string]func(interface{}, io.Reader) error{
+		"FromYaml":       FromYaml,
+		"FromYamlStrict": FromYamlStrict,
+	} {
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			var out interface{}
+			err := decode(&out, strings.NewReader(data))
+			g.Expect(err).Should(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring("excessive aliasing"))
+		})
+	}
+}
+
+func TestFromYamlStrict_recursiveAlias(t *testing.T) {
+	g := NewWithT(t)
+	var out interface{}
+	err := FromYamlStrict(&out, strings.NewReader("a: &a\n  b: *a\n"))
+	g.Expect(err).To(MatchError("yaml: line 2: anchor 'a' value contains itself"))
+}
@@ -0,0 +1,124 @@
+package decode
+
+import (
+	"gopkg.in/yaml.v3"
+
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
+
+	"bytes"
+	"io"
+)
+
+// YamlStream reads the AsyncAPI documents of a YAML stream, in which the documents are separated by ---.
+// The documents are read and decoded one by one like with FromYaml, or FromYamlStrict if Strict is set,
+// so the stream is never held in memory as a whole.
+// The empty documents, for example the one following the --- at the end of the stream, are skipped.
+//
+// The YamlStream can be passed to the ConvertStream method of the stream converter:
+//
+//	converter, err := v2.NewStream()
+//	err = converter.ConvertStream(decode.NewYamlStream(reader), encode.NewYamlStream(writer))
+type YamlStream struct {
+	// Strict enables the strict YAML decoding, see FromYamlStrict.
+	Strict bool
+
+	limits  Limits
+	reader  io.Reader
+	input   *streamReader
+	decoder *yaml.Decoder
+}
+
+// NewYamlStream returns the YamlStream reading the documents from the reader.
+func NewYamlStream(reader io.Reader) *YamlStream {
+	return Limits{}.NewYamlStream(reader)
+}
+
+// NewYamlStream returns the YamlStream reading the documents from the reader within the limits.
+// The MaxBytes limit bounds the size of the whole stream, the other limits bound every document.
+func (l Limits) NewYamlStream(reader io.Reader) *YamlStream {
+	return &YamlStream{
+		limits: l,
+		reader: reader,
+	}
+}
+
+// Decode reads the next document of the stream and stores it in the value.
+// It returns io.EOF when there are no more documents.
+func (s *YamlStream) Decode(v interface{}) error {
+	if s.decoder == nil {
+		s.input = &streamReader{reader: s.limits.limitReader(s.reader), maxDepth: s.limits.MaxDepth}
+		s.input.scanner.limit = s.limits.MaxDepth
+		s.decoder = yaml.NewDecoder(s.input)
+	}
+	for {
+		var node yaml.Node
+		if err := s.decoder.Decode(&node); err != nil {
+			if s.input.err != nil && s.input.err != io.EOF {
+				// the parser reports the errors of the reader as input errors, which hide the LimitExceeded errors
+				return s.input.err
+			}
+			return err
+		}
+		if !isEmptyDocument(&node) {
+			return s.limits.decodeYamlNode(&node, v, s.Strict)
+		}
+	}
+}
+
+// streamReader passes the stream to the parser line by line. If the depth is limited, every line is checked
+// before the parser reads it, so the parser recursion is bounded without reading the whole stream, see flowDepth.
+type streamReader struct {
+	reader   io.Reader
+	maxDepth int
+	scanner  flowScanner
+	// checked holds the checked lines the parser has not read yet, and line the start of the next line.
+	checked []byte
+	line    []byte
+	err     error
+}
+
+func (r *streamReader) Read(p []byte) (int, error) {
+	for len(r.checked) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.fill()
+	}
+	n := copy(p, r.checked)
+	r.checked = r.checked[n:]
+	return n, nil
+}
+
+// fill reads the next part of the stream and checks the lines completed by it.
+func (r *streamReader) fill() {
+	buf := make([]byte, 4096)
+	n, err := r.reader.Read(buf)
+	r.line = append(r.line, buf[:n]...)
+	end := bytes.LastIndexByte(r.line, '\n') + 1
+	if err != nil {
+		r.err = err
+		end = len(r.line)
+	}
+	for _, line := range bytes.SplitAfter(r.line[:end], []byte("\n")) {
+		if r.maxDepth > 0 {
+			// the line is passed up to the bracket exceeding the depth, so the preceding documents can be decoded
+			if index := r.scanner.scanLine(bytes.TrimSuffix(line, []byte("\n"))); index >= 0 {
+				r.checked = append(r.checked, line[:index]...)
+				r.err = asyncapierr.NewLimitExceeded("depth", r.maxDepth)
+				return
+			}
+		}
+		r.checked = append(r.checked, line...)
+	}
+	r.line = append([]byte(nil), r.line[end:]...)
+}
+
+// isEmptyDocument reports whether the document has no content, such as the document between two ---.
+func isEmptyDocument(node *yaml.Node) bool {
+	if len(node.Content) != 1 {
+		return len(node.Content) == 0
+	}
+	content := node.Content[0]
+	return content.Kind == yaml.ScalarNode && content.Tag == "!!null" && content.Value == "" &&
+		content.Style == 0 && content.Anchor == ""
+}
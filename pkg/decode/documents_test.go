@@ -0,0 +1,150 @@
+package decode
+
+import (
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
+	. "github.com/onsi/gomega"
+
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestYamlStream(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []interface{}
+	}{
+		{
+			name:  "empty stream",
+			input: "",
+		},
+		{
+			name:     "single document",
+			input:    "a: 1\n",
+			expected: []interface{}{map[string]interface{}{"a": json.Number("1")}},
+		},
+		{
+			name:  "documents with separators",
+			input: "---\na: 1\n---\n---\nb: [x]\n---\n",
+			expected: []interface{}{
+				map[string]interface{}{"a": json.Number("1")},
+				map[string]interface{}{"b": []interface{}{"x"}},
+			},
+		},
+		{
+			name:     "null document",
+			input:    "null\n",
+			expected: []interface{}{nil},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			stream := NewYamlStream(strings.NewReader(test.input))
+			var documents []interface{}
+			for {
+				var document interface{}
+				err := stream.Decode(&document)
+				if err == io.EOF {
+					break
+				}
+				g.Expect(err).ShouldNot(HaveOccurred())
+				documents = append(documents, document)
+			}
+			g.Expect(documents).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestYamlStream_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		stream  func(io.Reader) *YamlStream
+		input   string
+		decoded int
+		check   func(error) bool
+	}{
+		{
+			name:    "syntax error in the second document",
+			stream:  NewYamlStream,
+			input:   "a: 1\n---\nb: [\n",
+			decoded: 1,
+			check: func(err error) bool {
+				return strings.Contains(err.Error(), "line 3")
+			},
+		},
+		{
+			name: "strict",
+			stream: func(reader io.Reader) *YamlStream {
+				stream := NewYamlStream(reader)
+				stream.Strict = true
+				return stream
+			},
+			input:   "a: 1\n---\nb: 1\nb: 2\n",
+			decoded: 1,
+			check: func(err error) bool {
+				_, ok := err.(KeyError)
+				return ok
+			},
+		},
+		{
+			name:   "size limit of the stream",
+			stream: Limits{MaxBytes: 8}.NewYamlStream,
+			input:  "a: 1\n---\nb: 2\n",
+			check:  asyncapierr.IsLimitExceeded,
+		},
+		{
+			name:    "size limit reached in the second document",
+			stream:  Limits{MaxBytes: 16}.NewYamlStream,
+			input:   "a: 1\n---\nb: 2345678\n",
+			decoded: 1,
+			check:   asyncapierr.IsLimitExceeded,
+		},
+		{
+			name:    "flow depth limit of a long line",
+			stream:  Limits{MaxDepth: 16}.NewYamlStream,
+			input:   "a: 1\n---\nb: " + strings.Repeat("[", 100000) + "\n",
+			decoded: 1,
+			check:   asyncapierr.IsLimitExceeded,
+		},
+		{
+			name:    "depth limit of a document",
+			stream:  Limits{MaxDepth: 2}.NewYamlStream,
+			input:   "a: 1\n---\nb:\n  c:\n    d: 1\n",
+			decoded: 1,
+			check:   asyncapierr.IsLimitExceeded,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			stream := test.stream(strings.NewReader(test.input))
+			var err error
+			for i := 0; i <= test.decoded; i++ {
+				var document interface{}
+				err = stream.Decode(&document)
+				if i < test.decoded {
+					g.Expect(err).ShouldNot(HaveOccurred())
+				}
+			}
+			g.Expect(err).Should(HaveOccurred())
+			g.Expect(test.check(err)).To(BeTrue(), err.Error())
+		})
+	}
+}
+
+func TestYamlStream_incremental(t *testing.T) {
+	g := NewWithT(t)
+	input := "a: 1\n---\n" + strings.Repeat("- b\n", 1<<14)
+	reader := strings.NewReader(input)
+	stream := Limits{MaxBytes: 2 << 20, MaxDepth: 4}.NewYamlStream(reader)
+	var document interface{}
+	g.Expect(stream.Decode(&document)).To(Succeed())
+	g.Expect(document).To(Equal(map[string]interface{}{"a": json.Number("1")}))
+	// the first document is decoded before the stream is read as a whole
+	g.Expect(reader.Len()).To(BeNumerically(">", 0))
+	g.Expect(stream.Decode(&document)).To(Succeed())
+	g.Expect(stream.Decode(&document)).To(Equal(io.EOF))
+}
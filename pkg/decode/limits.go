@@ -0,0 +1,175 @@
+package decode
+
+import (
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
+
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+)
+
+// Limits bound the decoded documents, so a document from an untrusted source cannot exhaust
+// the memory or the stack. Exceeding a limit results in the LimitExceeded error from pkg/error.
+// A zero limit is not checked, so the zero value decodes like the functions of the package.
+//
+// The methods of Limits can be passed to the converter as the Decode function:
+//
+//	limits := decode.Limits{MaxBytes: 1 << 20, MaxDepth: 64, MaxAliasExpansions: 1000}
+//	converter, err := v2.New(limits.FromYaml, encode.ToJSON)
+type Limits struct {
+	// MaxBytes is the maximum size of the document in bytes.
+	MaxBytes int64
+	// MaxDepth is the maximum nesting depth of the mappings and sequences of the document.
+	MaxDepth int
+	// MaxAliasExpansions is the maximum number of YAML nodes decoded through the aliases.
+	MaxAliasExpansions int
+}
+
+// FromJSON works like the FromJSON function within the limits.
+func (l Limits) FromJSON(v interface{}, reader io.Reader) error {
+	if l.MaxBytes <= 0 && l.MaxDepth <= 0 {
+		return decodeJSON(reader, v)
+	}
+	data, err := l.read(reader)
+	if err != nil {
+		return err
+	}
+	if err := l.checkJSONDepth(data); err != nil {
+		return err
+	}
+	return decodeJSON(bytes.NewReader(data), v)
+}
+
+// FromYaml works like the FromYaml function within the limits.
+func (l Limits) FromYaml(v interface{}, reader io.Reader) error {
+	data, err := l.read(reader)
+	if err != nil {
+		return err
+	}
+	return l.unmarshalYaml(data, v, false)
+}
+
+// FromYamlStrict works like the FromYamlStrict function within the limits.
+func (l Limits) FromYamlStrict(v interface{}, reader io.Reader) error {
+	data, err := l.read(reader)
+	if err != nil {
+		return err
+	}
+	return l.unmarshalYaml(data, v, true)
+}
+
+// FromJSONWithYamlFallback works like the FromJSONWithYamlFallback function within the limits.
+func (l Limits) FromJSONWithYamlFallback(v interface{}, reader io.Reader) error {
+	data, err := l.read(reader)
+	if err != nil {
+		return err
+	}
+	jsonErr := l.unmarshalJSON(data, v)
+	if jsonErr == nil || asyncapierr.IsLimitExceeded(jsonErr) {
+		return jsonErr
+	}
+	yamlErr := l.unmarshalYaml(data, v, false)
+	if yamlErr == nil || asyncapierr.IsLimitExceeded(yamlErr) {
+		return yamlErr
+	}
+	return Error{JSON: jsonErr, YAML: yamlErr}
+}
+
+// read reads the whole document, but no more than one byte over the maximum size.
+func (l Limits) read(reader io.Reader) ([]byte, error) {
+	if l.MaxBytes <= 0 {
+		return ioutil.ReadAll(reader)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(reader, l.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > l.MaxBytes {
+		return nil, asyncapierr.NewLimitExceeded("size in bytes", l.MaxBytes)
+	}
+	return data, nil
+}
+
+// limitReader returns the reader failing with the LimitExceeded error once more than the maximum size is read,
+// so the documents read in parts are limited without being read as a whole.
+func (l Limits) limitReader(reader io.Reader) io.Reader {
+	if l.MaxBytes <= 0 {
+		return reader
+	}
+	return &limitedReader{reader: reader, max: l.MaxBytes}
+}
+
+type limitedReader struct {
+	reader io.Reader
+	max    int64
+	read   int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.read > r.max {
+		return 0, asyncapierr.NewLimitExceeded("size in bytes", r.max)
+	}
+	if remaining := r.max - r.read + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	if r.read > r.max {
+		return n, asyncapierr.NewLimitExceeded("size in bytes", r.max)
+	}
+	return n, err
+}
+
+func (l Limits) unmarshalJSON(data []byte, out interface{}) error {
+	if err := l.checkJSONDepth(data); err != nil {
+		return err
+	}
+	if !json.Valid(data) {
+		// json.Unmarshal reports the syntax error with its offset, unlike the decoder reading the first value only.
+		return json.Unmarshal(data, out)
+	}
+	return decodeJSON(bytes.NewReader(data), out)
+}
+
+// decodeJSON decodes the numbers as json.Number, so the numbers keep the exact text, for example
+// the 64-bit integers that float64 cannot represent.
+func decodeJSON(reader io.Reader, v interface{}) error {
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	return decoder.Decode(&v)
+}
+
+func (l Limits) checkJSONDepth(data []byte) error {
+	if l.MaxDepth > 0 && jsonDepth(data) > l.MaxDepth {
+		return asyncapierr.NewLimitExceeded("depth", l.MaxDepth)
+	}
+	return nil
+}
+
+// jsonDepth returns the nesting depth of the objects and arrays of the JSON document.
+// The brackets inside the strings are skipped.
+func jsonDepth(data []byte) int {
+	depth, max := 0, 0
+	inString, escaped := false, false
+	for _, b := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && b == '\\':
+			escaped = true
+		case inString:
+			inString = b != '"'
+		case b == '"':
+			inString = true
+		case b == '[' || b == '{':
+			depth++
+			if depth > max {
+				max = depth
+			}
+		case b == ']' || b == '}':
+			depth--
+		}
+	}
+	return max
+}
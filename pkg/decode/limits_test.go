@@ -0,0 +1,166 @@
+package decode
+
+import (
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
+	. "github.com/onsi/gomega"
+
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		limits    Limits
+		decode    func(Limits) func(interface{}, io.Reader) error
+		data      string
+		shouldErr bool
+	}{
+		{
+			name:   "json within limits",
+			limits: Limits{MaxBytes: 64, MaxDepth: 3},
+			decode: func(l Limits) func(interface{}, io.Reader) error { return l.FromJSON },
+			data:   `{"a": {"b": ["[[[{{{"]}}`,
+		},
+		{
+			name:      "json too large",
+			limits:    Limits{MaxBytes: 8},
+			decode:    func(l Limits) func(interface{}, io.Reader) error { return l.FromJSON },
+			data:      `{"a": "bcdef"}`,
+			shouldErr: true,
+		},
+		{
+			name:      "json too deep",
+			limits:    Limits{MaxDepth: 2},
+			decode:    func(l Limits) func(interface{}, io.Reader) error { return l.FromJSON },
+			data:      `{"a": {"b": []}}`,
+			shouldErr: true,
+		},
+		{
+			name:   "yaml within limits",
+			limits: Limits{MaxBytes: 64, MaxDepth: 3, MaxAliasExpansions: 3},
+			decode: func(l Limits) func(interface{}, io.Reader) error { return l.FromYaml },
+			data:   "a: &a {b: [c]}\nd: *a\n",
+		},
+		{
+			name:      "yaml too large",
+			limits:    Limits{MaxBytes: 8},
+			decode:    func(l Limits) func(interface{}, io.Reader) error { return l.FromYaml },
+			data:      "a: bcdefgh",
+			shouldErr: true,
+		},
+		{
+			name:      "yaml block mappings too deep",
+			limits:    Limits{MaxDepth: 2},
+			decode:    func(l Limits) func(interface{}, io.Reader) error { return l.FromYamlStrict },
+			data:      "a:\n  b:\n    c: d\n",
+			shouldErr: true,
+		},
+		{
+			name:      "yaml aliases too deep",
+			limits:    Limits{MaxDepth: 3},
+			decode:    func(l Limits) func(interface{}, io.Reader) error { return l.FromYaml },
+			data:      "a: &a\n  b:\n    c: d\ne:\n  f: *a\n",
+			shouldErr: true,
+		},
+		{
+			name:      "yaml flow collections too deep",
+			limits:    Limits{MaxDepth: 100},
+			decode:    func(l Limits) func(interface{}, io.Reader) error { return l.FromYaml },
+			data:      strings.Repeat("[", 200000),
+			shouldErr: true,
+		},
+		{
+			name:   "yaml brackets in scalars and comments within limits",
+			limits: Limits{MaxDepth: 3},
+			decode: func(l Limits) func(interface{}, io.Reader) error { return l.FromYaml },
+			data: "a: \"[[[{{{\"\nb: '[[''[[['\nc: ^[[[a-z]+$ # [[[\n" +
+				"d: |\n  [[[[\n  {{{{\ne: [\"[[[\", {f: '{{{'}]\n",
+		},
+		{
+			name:      "yaml too many alias expansions",
+			limits:    Limits{MaxAliasExpansions: 3},
+			decode:    func(l Limits) func(interface{}, io.Reader) error { return l.FromYaml },
+			data:      "a: &a [b, c]\nd: *a\ne: *a\n",
+			shouldErr: true,
+		},
+		{
+			name:      "fallback too deep",
+			limits:    Limits{MaxDepth: 1},
+			decode:    func(l Limits) func(interface{}, io.Reader) error { return l.FromJSONWithYamlFallback },
+			data:      "a:\n  b: c\n",
+			shouldErr: true,
+		},
+		{
+			name:      "auto too large",
+			limits:    Limits{MaxBytes: 4},
+			decode:    func(l Limits) func(interface{}, io.Reader) error { return (&Auto{Limits: l}).Decode },
+			data:      `{"a": 1}`,
+			shouldErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var out interface{}
+			err := test.decode(test.limits)(&out, strings.NewReader(test.data))
+			if test.shouldErr {
+				g.Expect(asyncapierr.IsLimitExceeded(err)).To(BeTrue(), "%v", err)
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+		})
+	}
+}
+
+func TestJSONDepth(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(jsonDepth([]byte(`"a"`))).To(Equal(0))
+	g.Expect(jsonDepth([]byte(`{"a": [1, {"b": "]}\"["}]}`))).To(Equal(3))
+}
+
+func TestFlowDepth(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected int
+	}{
+		{
+			name:     "flow collections",
+			data:     "a: [b, {c: [d]}]\ne:\n  - {f: g}\n",
+			expected: 3,
+		},
+		{
+			name:     "flow collection spanning lines",
+			data:     "a: [\n  [b],\n  \"c\"]\n",
+			expected: 2,
+		},
+		{
+			name:     "quoted scalars",
+			data:     "a: \"[{\\\"[{\"\nb: '[{''[{'\nc: [\"[[\", '{{']\n",
+			expected: 1,
+		},
+		{
+			name:     "plain scalars",
+			data:     "a: ^[a-z]+$\nb: x{y}\nc: it's [not] quoted\n",
+			expected: 0,
+		},
+		{
+			name:     "comments",
+			data:     "# [[\na: b # {{\n",
+			expected: 0,
+		},
+		{
+			name:     "block scalars",
+			data:     "a: |\n  [[\n\n  {{\nb: >-\n  [[\nc: [d]\n",
+			expected: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(flowDepth([]byte(test.data))).To(Equal(test.expected))
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package decode
+
+import (
+	"fmt"
+	"io"
+)
+
+// KeyError is returned by the YAML decoding when a mapping key is defined more than once in the same mapping,
+// and by the strict YAML decoding when a mapping key is not a string.
+type KeyError struct {
+	// Line is the line of the key in the document.
+	Line int
+	// Key is the exact text of the key.
+	Key string
+	// Reason describes why the key is rejected.
+	Reason string
+}
+
+func (err KeyError) Error() string {
+	return fmt.Sprintf("yaml: line %d: mapping key %q %s", err.Line, err.Key, err.Reason)
+}
+
+// FromYamlStrict reads an AsyncAPI document from input in the YAML format and stores it in the value.
+// Unlike FromYaml, it returns the KeyError for duplicated keys and for keys that are not strings,
+// such as 200, true or null, instead of converting them. The keys keep the exact text of the scalar,
+// so keys like on or yes, which YAML 1.1 treats as booleans, are decoded as written.
+// Like FromYaml, it expands the aliases and merge keys into independent copies of the anchored values.
+//
+// See InvalidProperty, InvalidDocument, UnsupportedAsyncapiVersion in pkg/error.
+func FromYamlStrict(v interface{}, reader io.Reader) error {
+	return Limits{}.FromYamlStrict(v, reader)
+}
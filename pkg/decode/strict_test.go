@@ -0,0 +1,116 @@
+package decode
+
+import (
+	. "github.com/onsi/gomega"
+
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFromYamlStrict(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected interface{}
+	}{
+		{
+			name: "exact key text",
+			data: "on: 1\nyes: 2\n\"200\": ok\n'0x10': hex\n",
+			expected: map[string]interface{}{
+				"on":   json.Number("1"),
+				"yes":  json.Number("2"),
+				"200":  "ok",
+				"0x10": "hex",
+			},
+		},
+		{
+			name: "nested",
+			data: "topics:\n  user.signedup:\n    publish:\n      - a\n      - b: true\n",
+			expected: map[string]interface{}{
+				"topics": map[string]interface{}{
+					"user.signedup": map[string]interface{}{
+						"publish": []interface{}{"a", map[string]interface{}{"b": true}},
+					},
+				},
+			},
+		},
+		{
+			name: "merge keys",
+			data: "base: &base {a: 1, b: 2}\nother: {b: 3}\nderived:\n  <<: [*base, {c: 4}]\n  a: 0\n",
+			expected: map[string]interface{}{
+				"base":    map[string]interface{}{"a": json.Number("1"), "b": json.Number("2")},
+				"other":   map[string]interface{}{"b": json.Number("3")},
+				"derived": map[string]interface{}{"a": json.Number("0"), "b": json.Number("2"), "c": json.Number("4")},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var out interface{}
+			g.Expect(FromYamlStrict(&out, strings.NewReader(test.data))).To(Succeed())
+			g.Expect(out).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestFromYamlStrict_empty(t *testing.T) {
+	g := NewWithT(t)
+	var out interface{} = "previous"
+	g.Expect(FromYamlStrict(&out, strings.NewReader(""))).To(Succeed())
+	g.Expect(out).To(BeNil())
+}
+
+func TestFromYamlStrict_error(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{
+			name:     "duplicated key",
+			data:     "a: 1\nb:\n  c: 2\n  c: 3\n",
+			expected: `yaml: line 4: mapping key "c" already defined at line 3`,
+		},
+		{
+			name:     "integer key",
+			data:     "responses:\n  200: ok\n",
+			expected: `yaml: line 2: mapping key "200" is not a string`,
+		},
+		{
+			name:     "null key",
+			data:     "a: 1\n~: 2\n",
+			expected: `yaml: line 2: mapping key "~" is not a string`,
+		},
+		{
+			name:     "boolean key",
+			data:     "true: 1\n",
+			expected: `yaml: line 1: mapping key "true" is not a string`,
+		},
+		{
+			name:     "syntax error",
+			data:     "a: [\n",
+			expected: "yaml: line 1: did not find expected node content",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var out interface{}
+			err := FromYamlStrict(&out, strings.NewReader(test.data))
+			g.Expect(err).Should(HaveOccurred())
+			g.Expect(err.Error()).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestAuto_Decode_strict(t *testing.T) {
+	g := NewWithT(t)
+	var out interface{}
+	auto := Auto{Strict: true}
+	err := auto.Decode(&out, strings.NewReader("a: 1\na: 2\n"))
+	g.Expect(err).To(BeAssignableToTypeOf(Error{}))
+	g.Expect(err.(Error).YAML).To(BeAssignableToTypeOf(KeyError{}))
+	g.Expect(err.Error()).To(HaveSuffix(`; yaml: line 2: mapping key "a" already defined at line 1`))
+}
@@ -0,0 +1,316 @@
+package decode
+
+import (
+	"gopkg.in/yaml.v3"
+
+	asyncapierr "github.com/asyncapi/converter-go/pkg/error"
+
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	strTag   = "!!str"
+	intTag   = "!!int"
+	floatTag = "!!float"
+	mergeTag = "!!merge"
+)
+
+// jsonNumberRegexp matches the numbers written in the JSON syntax.
+var jsonNumberRegexp = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+
+// nodeDecoder decodes the YAML nodes into new values, so every alias results in an independent copy
+// of the anchored value. Like yaml.Unmarshal, it rejects recursive aliases and the documents
+// that are mostly made of alias expansions, such as the billion laughs attack.
+type nodeDecoder struct {
+	limits      Limits
+	strict      bool
+	aliases     map[*yaml.Node]bool
+	aliasDepth  int
+	aliasCount  int
+	decodeCount int
+	depth       int
+}
+
+func (l Limits) unmarshalYaml(in []byte, out interface{}, strict bool) error {
+	if err := l.checkYamlDepth(in); err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(in, &node); err != nil {
+		return err
+	}
+	return l.decodeYamlNode(&node, out, strict)
+}
+
+// decodeYamlNode decodes the parsed YAML document node and stores the result in the value.
+func (l Limits) decodeYamlNode(node *yaml.Node, out interface{}, strict bool) error {
+	if node.Kind == 0 {
+		*out.(*interface{}) = nil
+		return nil
+	}
+	d := nodeDecoder{
+		limits:  l,
+		strict:  strict,
+		aliases: make(map[*yaml.Node]bool),
+	}
+	result, err := d.decode(node)
+	if err != nil {
+		return err
+	}
+	*out.(*interface{}) = result
+	return nil
+}
+
+// checkYamlDepth checks the depth of the flow collections before the document is parsed, the depth
+// of the block collections is checked as the nodes are decoded.
+func (l Limits) checkYamlDepth(data []byte) error {
+	if l.MaxDepth > 0 && flowDepth(data) > l.MaxDepth {
+		return asyncapierr.NewLimitExceeded("depth", l.MaxDepth)
+	}
+	return nil
+}
+
+// flowDepth returns the nesting depth of the flow collections of the document. The parser recursion is checked
+// with it before parsing, as the parser descends into every flow collection. The brackets inside the quoted
+// scalars, the block scalars and the comments are skipped, and outside of the flow collections only a bracket
+// starting a value opens a collection, so brackets of the plain scalars, such as ^[a-z]+$, are not counted.
+func flowDepth(data []byte) int {
+	var scanner flowScanner
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		scanner.scanLine(line)
+	}
+	return scanner.max
+}
+
+// flowScanner tracks the flow collections across the lines of the document.
+type flowScanner struct {
+	depth int
+	max   int
+	// limit stops the scanning of a line at the bracket exceeding the depth, if it is positive.
+	limit int
+	// quote is the quote of the scalar continued on the next line, or 0.
+	quote byte
+	// blockScalar is set if the next lines indented more than blockIndent belong to a block scalar.
+	blockScalar bool
+	blockIndent int
+}
+
+// scanLine scans the line and returns the index of the bracket exceeding the limit, or -1.
+func (s *flowScanner) scanLine(line []byte) int {
+	indent := len(line) - len(bytes.TrimLeft(line, " "))
+	if s.blockScalar {
+		if indent > s.blockIndent || len(bytes.TrimSpace(line)) == 0 {
+			return -1
+		}
+		s.blockScalar = false
+	}
+	valueStart, prev := true, byte(' ')
+	for i := 0; i < len(line); i++ {
+		b := line[i]
+		switch {
+		case s.quote == '"' && b == '\\':
+			i++
+			continue
+		case s.quote == '\'' && b == '\'' && i+1 < len(line) && line[i+1] == '\'':
+			i++
+			continue
+		case s.quote != 0:
+			if b == s.quote {
+				s.quote = 0
+			}
+		case b == '#' && isYamlSpace(prev):
+			return -1
+		case (b == '"' || b == '\'') && valueStart:
+			s.quote = b
+		case (b == '[' || b == '{') && (valueStart || s.depth > 0):
+			s.depth++
+			if s.depth > s.max {
+				s.max = s.depth
+			}
+			if s.limit > 0 && s.depth > s.limit {
+				return i
+			}
+		case (b == ']' || b == '}') && s.depth > 0:
+			s.depth--
+		case (b == '|' || b == '>') && valueStart && s.depth == 0:
+			s.blockScalar, s.blockIndent = true, indent
+			return -1
+		}
+		switch {
+		case s.quote != 0:
+			valueStart = false
+		case isYamlSpace(b):
+			valueStart = valueStart || prev == ':' || prev == '-' || prev == '?'
+		default:
+			valueStart = s.depth > 0 && strings.IndexByte("[{,:", b) >= 0
+		}
+		prev = b
+	}
+	return -1
+}
+
+func isYamlSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r'
+}
+
+func (d *nodeDecoder) decode(node *yaml.Node) (interface{}, error) {
+	d.decodeCount++
+	if d.aliasDepth > 0 {
+		d.aliasCount++
+	}
+	if d.limits.MaxAliasExpansions > 0 && d.aliasCount > d.limits.MaxAliasExpansions {
+		return nil, asyncapierr.NewLimitExceeded("alias expansion", d.limits.MaxAliasExpansions)
+	}
+	if d.aliasCount > 100 && d.decodeCount > 1000 && float64(d.aliasCount)/float64(d.decodeCount) > 0.99 {
+		return nil, errors.New("yaml: document contains excessive aliasing")
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return d.decode(node.Content[0])
+	case yaml.SequenceNode:
+		if err := d.enter(); err != nil {
+			return nil, err
+		}
+		defer d.leave()
+		result := make([]interface{}, len(node.Content))
+		for i, item := range node.Content {
+			value, err := d.decode(item)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = value
+		}
+		return result, nil
+	case yaml.MappingNode:
+		if err := d.enter(); err != nil {
+			return nil, err
+		}
+		defer d.leave()
+		return d.decodeMapping(node)
+	case yaml.AliasNode:
+		return d.decodeAlias(node)
+	default:
+		return decodeScalar(node)
+	}
+}
+
+// decodeScalar decodes the numbers written in the JSON syntax as json.Number, so they keep the exact text
+// like the numbers decoded from JSON. The other numbers, for example 0x10 or .inf, are decoded by their value.
+func decodeScalar(node *yaml.Node) (interface{}, error) {
+	if tag := node.ShortTag(); (tag == intTag || tag == floatTag) && jsonNumberRegexp.MatchString(node.Value) {
+		return json.Number(node.Value), nil
+	}
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (d *nodeDecoder) enter() error {
+	d.depth++
+	if d.limits.MaxDepth > 0 && d.depth > d.limits.MaxDepth {
+		return asyncapierr.NewLimitExceeded("depth", d.limits.MaxDepth)
+	}
+	return nil
+}
+
+func (d *nodeDecoder) leave() {
+	d.depth--
+}
+
+func (d *nodeDecoder) decodeAlias(node *yaml.Node) (interface{}, error) {
+	if d.aliases[node] {
+		return nil, fmt.Errorf("yaml: line %d: anchor '%s' value contains itself", node.Line, node.Value)
+	}
+	d.aliases[node] = true
+	d.aliasDepth++
+	defer func() {
+		d.aliasDepth--
+		delete(d.aliases, node)
+	}()
+	return d.decode(node.Alias)
+}
+
+// decodeMapping decodes the mapping with the keys defined in it taking precedence over the merged keys.
+func (d *nodeDecoder) decodeMapping(node *yaml.Node) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(node.Content)/2)
+	lines := make(map[string]int, len(node.Content)/2)
+	var merged []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		if keyNode.Kind == yaml.ScalarNode && keyNode.ShortTag() == mergeTag {
+			merged = append(merged, valueNode)
+			continue
+		}
+		key, err := d.key(keyNode)
+		if err != nil {
+			return nil, err
+		}
+		if line, ok := lines[key]; ok {
+			return nil, KeyError{Line: keyNode.Line, Key: keyNode.Value, Reason: fmt.Sprintf("already defined at line %d", line)}
+		}
+		lines[key] = keyNode.Line
+		value, err := d.decode(valueNode)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+	for _, valueNode := range merged {
+		if err := d.merge(result, valueNode); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// key returns the text of the string keys. The strict decoding rejects the other keys,
+// otherwise they are formatted with fmt, for example 200 or true.
+func (d *nodeDecoder) key(node *yaml.Node) (string, error) {
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == strTag {
+		return node.Value, nil
+	}
+	if d.strict {
+		return "", KeyError{Line: node.Line, Key: node.Value, Reason: "is not a string"}
+	}
+	key, err := d.decode(node)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v", key), nil
+}
+
+// merge adds the keys of the merged mapping, or of the sequence of merged mappings, that are not defined yet.
+func (d *nodeDecoder) merge(result map[string]interface{}, node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		for _, item := range node.Content {
+			if err := d.merge(result, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	value, err := d.decode(node)
+	if err != nil {
+		return err
+	}
+	mapping, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("yaml: line %d: map merge requires map or sequence of maps as the value", node.Line)
+	}
+	for key, item := range mapping {
+		if _, ok := result[key]; !ok {
+			result[key] = item
+		}
+	}
+	return nil
+}
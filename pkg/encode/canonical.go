@@ -0,0 +1,290 @@
+package encode
+
+import (
+	"golang.org/x/text/unicode/norm"
+
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Canonical is a functional option that enables the canonical encoding, which writes the same document
+// always in the same bytes, so the converted documents can be committed and compared:
+//
+//   - the keys are ordered with AsyncAPIKeys,
+//   - the numbers are written in the shortest exact form, for example 1.50 as 1.5, 1E+2 as 100 and -0 as 0,
+//   - the strings are normalized to the Unicode NFC form, with the \r\n and \r line endings replaced with \n,
+//   - the <, > and & characters are not escaped,
+//   - the document ends with a newline.
+//
+// The indentation and the YAML flow lists can still be configured with the options following Canonical.
+func Canonical() Option {
+	return func(options *options) {
+		options.keyOrder = AsyncAPIKeys
+		options.escapeHTML = false
+		options.trailingNewline = true
+		options.canonical = true
+	}
+}
+
+// AsyncAPIKeys orders the keys of the AsyncAPI 2.0.0 objects in the order of the fields in the specification,
+// for example asyncapi, id, info, servers, channels, components, tags and externalDocs in the document.
+// The JSON Schema keywords of the schemas are ordered from the metadata, like title, description and type,
+// to the validation and composition keywords. The object kinds are recognized from the path.
+//
+// The keys that are not fields of the object, such as the extensions, and the keys of the maps,
+// such as the channel names or the schema properties, follow in alphabetical order.
+func AsyncAPIKeys(path string, keys []string) {
+	kind := documentKind
+	for _, token := range strings.Split(path, "/")[1:] {
+		if kind == nil {
+			break
+		}
+		kind = kind.child(strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1))
+	}
+	kind.sort(keys)
+}
+
+// objectKind describes an AsyncAPI object, or a map of the objects if it has no fields.
+type objectKind struct {
+	fields []string
+	// children holds the kinds of the field values.
+	children map[string]*objectKind
+	// values is the kind of the map values.
+	values *objectKind
+}
+
+// child returns the kind of the value of the key. The list items are of the kind of the list,
+// so the index tokens keep the kind.
+func (k *objectKind) child(key string) *objectKind {
+	if child, ok := k.children[key]; ok {
+		return child
+	}
+	if k.values != nil {
+		return k.values
+	}
+	if _, err := strconv.Atoi(key); err == nil {
+		return k
+	}
+	return nil
+}
+
+func (k *objectKind) sort(keys []string) {
+	var positions map[string]int
+	if k != nil {
+		positions = make(map[string]int, len(k.fields))
+		for i, field := range k.fields {
+			positions[field] = i
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		first, firstOk := positions[keys[i]]
+		second, secondOk := positions[keys[j]]
+		switch {
+		case firstOk && secondOk:
+			return first < second
+		case firstOk != secondOk:
+			return firstOk
+		default:
+			return keys[i] < keys[j]
+		}
+	})
+}
+
+var (
+	documentKind       = &objectKind{fields: []string{"asyncapi", "id", "info", "servers", "channels", "components", "tags", "externalDocs"}}
+	infoKind           = &objectKind{fields: []string{"title", "version", "description", "termsOfService", "contact", "license"}}
+	contactKind        = &objectKind{fields: []string{"name", "url", "email"}}
+	licenseKind        = &objectKind{fields: []string{"name", "url"}}
+	serverKind         = &objectKind{fields: []string{"url", "protocol", "protocolVersion", "description", "variables", "security", "bindings"}}
+	serverVariableKind = &objectKind{fields: []string{"enum", "default", "description", "examples"}}
+	channelKind        = &objectKind{fields: []string{"$ref", "description", "subscribe", "publish", "parameters", "bindings"}}
+	operationKind      = &objectKind{fields: []string{"operationId", "summary", "description", "tags", "externalDocs", "bindings", "traits", "message"}}
+	operationTraitKind = &objectKind{fields: []string{"$ref", "operationId", "summary", "description", "tags", "externalDocs", "bindings"}}
+	parameterKind      = &objectKind{fields: []string{"$ref", "description", "schema", "location"}}
+	messageKind        = &objectKind{fields: []string{"$ref", "oneOf", "headers", "payload", "correlationId", "schemaFormat", "contentType",
+		"name", "title", "summary", "description", "tags", "externalDocs", "bindings", "examples", "traits"}}
+	messageTraitKind = &objectKind{fields: []string{"$ref", "headers", "correlationId", "schemaFormat", "contentType",
+		"name", "title", "summary", "description", "tags", "externalDocs", "bindings", "examples"}}
+	componentsKind = &objectKind{fields: []string{"schemas", "messages", "securitySchemes", "parameters", "correlationIds",
+		"operationTraits", "messageTraits", "serverBindings", "channelBindings", "operationBindings", "messageBindings"}}
+	tagKind            = &objectKind{fields: []string{"name", "description", "externalDocs"}}
+	externalDocsKind   = &objectKind{fields: []string{"description", "url"}}
+	correlationIDKind  = &objectKind{fields: []string{"$ref", "description", "location"}}
+	securitySchemeKind = &objectKind{fields: []string{"$ref", "type", "description", "name", "in", "scheme", "bearerFormat",
+		"flows", "openIdConnectUrl"}}
+	oauthFlowsKind = &objectKind{fields: []string{"implicit", "password", "clientCredentials", "authorizationCode"}}
+	oauthFlowKind  = &objectKind{fields: []string{"authorizationUrl", "tokenUrl", "refreshUrl", "scopes"}}
+	schemaKind     = &objectKind{fields: []string{"$ref", "$id", "$schema", "title", "description", "type", "format", "default",
+		"enum", "const", "multipleOf", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum", "maxLength", "minLength",
+		"pattern", "items", "additionalItems", "maxItems", "minItems", "uniqueItems", "contains", "properties",
+		"patternProperties", "additionalProperties", "required", "maxProperties", "minProperties", "propertyNames",
+		"dependencies", "definitions", "allOf", "anyOf", "oneOf", "not", "if", "then", "else", "discriminator", "readOnly",
+		"writeOnly", "deprecated", "externalDocs", "examples", "example"}}
+)
+
+// mapOf returns the kind of a map of the objects of the kind.
+func mapOf(kind *objectKind) *objectKind {
+	return &objectKind{values: kind}
+}
+
+func init() {
+	documentKind.children = map[string]*objectKind{
+		"info":         infoKind,
+		"servers":      mapOf(serverKind),
+		"channels":     mapOf(channelKind),
+		"components":   componentsKind,
+		"tags":         tagKind,
+		"externalDocs": externalDocsKind,
+	}
+	infoKind.children = map[string]*objectKind{"contact": contactKind, "license": licenseKind}
+	serverKind.children = map[string]*objectKind{"variables": mapOf(serverVariableKind)}
+	channelKind.children = map[string]*objectKind{
+		"subscribe":  operationKind,
+		"publish":    operationKind,
+		"parameters": mapOf(parameterKind),
+	}
+	operationKind.children = map[string]*objectKind{
+		"tags":         tagKind,
+		"externalDocs": externalDocsKind,
+		"traits":       operationTraitKind,
+		"message":      messageKind,
+	}
+	operationTraitKind.children = map[string]*objectKind{"tags": tagKind, "externalDocs": externalDocsKind}
+	parameterKind.children = map[string]*objectKind{"schema": schemaKind}
+	messageKind.children = map[string]*objectKind{
+		"oneOf":         messageKind,
+		"headers":       schemaKind,
+		"payload":       schemaKind,
+		"correlationId": correlationIDKind,
+		"tags":          tagKind,
+		"externalDocs":  externalDocsKind,
+		"traits":        messageTraitKind,
+	}
+	messageTraitKind.children = map[string]*objectKind{
+		"headers":       schemaKind,
+		"correlationId": correlationIDKind,
+		"tags":          tagKind,
+		"externalDocs":  externalDocsKind,
+	}
+	componentsKind.children = map[string]*objectKind{
+		"schemas":         mapOf(schemaKind),
+		"messages":        mapOf(messageKind),
+		"securitySchemes": mapOf(securitySchemeKind),
+		"parameters":      mapOf(parameterKind),
+		"correlationIds":  mapOf(correlationIDKind),
+		"operationTraits": mapOf(operationTraitKind),
+		"messageTraits":   mapOf(messageTraitKind),
+	}
+	tagKind.children = map[string]*objectKind{"externalDocs": externalDocsKind}
+	securitySchemeKind.children = map[string]*objectKind{"flows": oauthFlowsKind}
+	oauthFlowsKind.children = map[string]*objectKind{
+		"implicit":          oauthFlowKind,
+		"password":          oauthFlowKind,
+		"clientCredentials": oauthFlowKind,
+		"authorizationCode": oauthFlowKind,
+	}
+	schemaKind.children = map[string]*objectKind{
+		"items":                schemaKind,
+		"additionalItems":      schemaKind,
+		"contains":             schemaKind,
+		"properties":           mapOf(schemaKind),
+		"patternProperties":    mapOf(schemaKind),
+		"additionalProperties": schemaKind,
+		"propertyNames":        schemaKind,
+		"dependencies":         mapOf(schemaKind),
+		"definitions":          mapOf(schemaKind),
+		"allOf":                schemaKind,
+		"anyOf":                schemaKind,
+		"oneOf":                schemaKind,
+		"not":                  schemaKind,
+		"if":                   schemaKind,
+		"then":                 schemaKind,
+		"else":                 schemaKind,
+		"externalDocs":         externalDocsKind,
+	}
+}
+
+// normalize returns the normalized value of the string or the number in the canonical encoding.
+func (o options) normalize(v interface{}) interface{} {
+	if !o.canonical {
+		return v
+	}
+	switch v := v.(type) {
+	case string:
+		return normalizeString(v)
+	case json.Number:
+		return normalizeNumber(v)
+	default:
+		return v
+	}
+}
+
+// normalizeString returns the string in the Unicode NFC form with the line endings replaced with \n.
+func normalizeString(s string) string {
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	s = strings.Replace(s, "\r", "\n", -1)
+	return norm.NFC.String(s)
+}
+
+// normalizeNumber returns the shortest exact form of the number: the integers are written without
+// the fraction and the exponent, for example 1.0 and 1E+2 as 1 and 100, the other numbers without
+// the trailing zeros, for example 1.50 as 1.5. The numbers with more than 21 integer digits or more than 5 leading
+// fraction zeros use the exponent, for example 1e+400 and 1.5e-7.
+func normalizeNumber(number json.Number) json.Number {
+	s := string(number)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimLeft(s, "+-")
+	exponent := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return number
+		}
+		exponent = e
+		s = s[:i]
+	}
+	// the digits are the significand of the number digits × 10^exponent
+	digits := s
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		digits = s[:i] + s[i+1:]
+		exponent -= len(s) - i - 1
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return number
+		}
+	}
+	digits = strings.TrimLeft(digits, "0")
+	trimmed := strings.TrimRight(digits, "0")
+	exponent += len(digits) - len(trimmed)
+	digits = trimmed
+	if digits == "" {
+		return "0"
+	}
+	sign := ""
+	if negative {
+		sign = "-"
+	}
+	// point is the position of the decimal point relative to the start of the digits
+	point := len(digits) + exponent
+	switch {
+	case point > 21 || point < -5:
+		mantissa := digits[:1]
+		if len(digits) > 1 {
+			mantissa += "." + digits[1:]
+		}
+		e := point - 1
+		if e >= 0 {
+			return json.Number(sign + mantissa + "e+" + strconv.Itoa(e))
+		}
+		return json.Number(sign + mantissa + "e" + strconv.Itoa(e))
+	case exponent >= 0:
+		return json.Number(sign + digits + strings.Repeat("0", exponent))
+	case point > 0:
+		return json.Number(sign + digits[:point] + "." + digits[point:])
+	default:
+		return json.Number(sign + "0." + strings.Repeat("0", -point) + digits)
+	}
+}
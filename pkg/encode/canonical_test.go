@@ -0,0 +1,152 @@
+package encode
+
+import (
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+func TestNormalizeNumber(t *testing.T) {
+	tests := []struct {
+		number   json.Number
+		expected json.Number
+	}{
+		{number: "0", expected: "0"},
+		{number: "-0.0", expected: "0"},
+		{number: "7", expected: "7"},
+		{number: "-12", expected: "-12"},
+		{number: "1.50", expected: "1.5"},
+		{number: "1.0", expected: "1"},
+		{number: "1E+2", expected: "100"},
+		{number: "0.001e3", expected: "1"},
+		{number: "12.5e-1", expected: "1.25"},
+		{number: "0.000001", expected: "0.000001"},
+		{number: "0.00000015", expected: "1.5e-7"},
+		{number: "1e20", expected: "100000000000000000000"},
+		{number: "1e21", expected: "1e+21"},
+		{number: "-1e400", expected: "-1e+400"},
+		{number: "18446744073709551616", expected: "18446744073709551616"},
+		{number: "123456789012345678901234", expected: "1.23456789012345678901234e+23"},
+	}
+	for _, test := range tests {
+		t.Run(string(test.number), func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(normalizeNumber(test.number)).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestAsyncAPIKeys(t *testing.T) {
+	tests := []struct {
+		path     string
+		keys     []string
+		expected []string
+	}{
+		{
+			path:     "",
+			keys:     []string{"x-internal", "servers", "info", "channels", "asyncapi", "components", "id"},
+			expected: []string{"asyncapi", "id", "info", "servers", "channels", "components", "x-internal"},
+		},
+		{
+			path:     "/channels",
+			keys:     []string{"user/signedup", "publish", "a"},
+			expected: []string{"a", "publish", "user/signedup"},
+		},
+		{
+			path:     "/channels/user~1signedup/publish/message",
+			keys:     []string{"payload", "name", "headers"},
+			expected: []string{"headers", "payload", "name"},
+		},
+		{
+			path:     "/channels/user~1signedup/publish/message/oneOf/1/payload/properties/id",
+			keys:     []string{"minimum", "x-example", "type", "description"},
+			expected: []string{"description", "type", "minimum", "x-example"},
+		},
+		{
+			path:     "/components/securitySchemes/oauth/flows/implicit",
+			keys:     []string{"scopes", "authorizationUrl"},
+			expected: []string{"authorizationUrl", "scopes"},
+		},
+		{
+			path:     "/x-internal",
+			keys:     []string{"title", "asyncapi"},
+			expected: []string{"asyncapi", "title"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			g := NewWithT(t)
+			AsyncAPIKeys(test.path, test.keys)
+			g.Expect(test.keys).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	document := map[string]interface{}{
+		"info": map[string]interface{}{
+			"version":     "1.0.0",
+			"description": "Café <lights>\r\n",
+			"title":       "Streetlights",
+		},
+		"asyncapi": "2.0.0",
+		"channels": map[string]interface{}{
+			"light": map[string]interface{}{
+				"publish": map[string]interface{}{
+					"message": map[string]interface{}{
+						"payload": map[string]interface{}{
+							"maximum": json.Number("1.00E+2"),
+							"type":    "number",
+						},
+					},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name     string
+		encoder  func(...Option) func(interface{}, io.Writer) error
+		options  []Option
+		expected string
+	}{
+		{
+			name:     "json",
+			encoder:  JSON,
+			options:  []Option{Canonical()},
+			expected: "{\"asyncapi\":\"2.0.0\",\"info\":{\"title\":\"Streetlights\",\"version\":\"1.0.0\",\"description\":\"Café <lights>\\n\"},\"channels\":{\"light\":{\"publish\":{\"message\":{\"payload\":{\"type\":\"number\",\"maximum\":100}}}}}}\n",
+		},
+		{
+			name:    "yaml",
+			encoder: YAML,
+			options: []Option{Canonical(), WithIndent(2)},
+			expected: `asyncapi: 2.0.0
+info:
+  title: Streetlights
+  version: 1.0.0
+  description: |
+    Café <lights>
+channels:
+  light:
+    publish:
+      message:
+        payload:
+          type: number
+          maximum: 100
+`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			encode := test.encoder(test.options...)
+			var first, second bytes.Buffer
+			g.Expect(encode(document, &first)).To(Succeed())
+			g.Expect(encode(document, &second)).To(Succeed())
+			g.Expect(first.String()).To(Equal(test.expected))
+			g.Expect(second.Bytes()).To(Equal(first.Bytes()))
+		})
+	}
+}
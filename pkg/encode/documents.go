@@ -0,0 +1,34 @@
+package encode
+
+import (
+	"io"
+)
+
+// YamlStream writes the AsyncAPI documents into a YAML stream, separating the documents with ---.
+// The stream of a single document is written like with the YAML encoder.
+type YamlStream struct {
+	writer io.Writer
+	encode func(interface{}, io.Writer) error
+	count  int
+}
+
+// NewYamlStream returns the YamlStream writing the documents to the writer.
+// The documents are encoded with the YAML encoder configured with the options,
+// but they always end with a newline, so the separators start on a new line.
+func NewYamlStream(writer io.Writer, options ...Option) *YamlStream {
+	return &YamlStream{
+		writer: writer,
+		encode: YAML(append(options[:len(options):len(options)], WithTrailingNewline(true))...),
+	}
+}
+
+// Encode writes the next document into the stream.
+func (s *YamlStream) Encode(v interface{}) error {
+	if s.count > 0 {
+		if _, err := io.WriteString(s.writer, "---\n"); err != nil {
+			return err
+		}
+	}
+	s.count++
+	return s.encode(v, s.writer)
+}
@@ -1,18 +1,195 @@
 package encode
 
 import (
-	"gopkg.in/yaml.v3"
-
-	"encoding/json"
 	"io"
+	"sort"
+	"strings"
+	"unicode"
 )
 
+// KeyOrder orders in place the keys of the mapping at the path, which is a JSON pointer,
+// for example /channels/user~1signedup.
+//
+// The decoded documents are maps that do not keep the order of the keys, so the encoders order the keys
+// of every mapping with the KeyOrder to write deterministic output. The order of the keys in the input
+// document is not available to the KeyOrder and cannot be restored.
+type KeyOrder func(path string, keys []string)
+
+// SortedKeys orders the keys alphabetically. It is the default KeyOrder of the JSON encoder,
+// which orders the keys like the encoding/json package.
+func SortedKeys(_ string, keys []string) {
+	sort.Strings(keys)
+}
+
+// NaturalKeys orders the keys alphabetically, but compares the numbers within the keys by their value,
+// for example server2 before server10. It is the default KeyOrder of the YAML encoder,
+// which orders the keys like the gopkg.in/yaml.v3 package.
+func NaturalKeys(_ string, keys []string) {
+	sort.Slice(keys, func(i, j int) bool {
+		return naturalLess([]rune(keys[i]), []rune(keys[j]))
+	})
+}
+
+// naturalLess compares the keys like the YAML encoder does.
+func naturalLess(a, b []rune) bool {
+	digits := false
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			digits = unicode.IsDigit(a[i])
+			continue
+		}
+		aLetter, bLetter := unicode.IsLetter(a[i]), unicode.IsLetter(b[i])
+		if aLetter && bLetter {
+			return a[i] < b[i]
+		}
+		if aLetter || bLetter {
+			if digits {
+				return aLetter
+			}
+			return bLetter
+		}
+		var aEnd, bEnd int
+		var aNumber, bNumber int64
+		if a[i] == '0' || b[i] == '0' {
+			for j := i - 1; j >= 0 && unicode.IsDigit(a[j]); j-- {
+				if a[j] != '0' {
+					aNumber, bNumber = 1, 1
+					break
+				}
+			}
+		}
+		for aEnd = i; aEnd < len(a) && unicode.IsDigit(a[aEnd]); aEnd++ {
+			aNumber = aNumber*10 + int64(a[aEnd]-'0')
+		}
+		for bEnd = i; bEnd < len(b) && unicode.IsDigit(b[bEnd]); bEnd++ {
+			bNumber = bNumber*10 + int64(b[bEnd]-'0')
+		}
+		if aNumber != bNumber {
+			return aNumber < bNumber
+		}
+		if aEnd != bEnd {
+			return aEnd < bEnd
+		}
+		return a[i] < b[i]
+	}
+	return len(a) < len(b)
+}
+
+type options struct {
+	indent int
+	// keyOrder is nil if the keys are ordered with the defaultKeyOrder of the encoder.
+	keyOrder        KeyOrder
+	defaultKeyOrder KeyOrder
+	escapeHTML      bool
+	trailingNewline bool
+	flowListItems   int
+	canonical       bool
+}
+
+// Option is a functional option that allows you to configure the encoders created with JSON and YAML.
+type Option func(*options)
+
+// WithIndent is a functional option that allows you to specify the number of spaces each nesting level
+// is indented with. The JSON encoder writes the document in a single line if the width is 0, which is the default.
+// The YAML encoder supports between 2 and 9 spaces and defaults to 4.
+func WithIndent(width int) Option {
+	return func(options *options) {
+		if width < 0 {
+			width = 0
+		}
+		options.indent = width
+	}
+}
+
+// WithKeyOrder is a functional option that allows you to specify the order of the keys of the mappings.
+// By default, the JSON encoder orders the keys with SortedKeys and the YAML encoder with NaturalKeys,
+// so the default output is byte-identical to the output of the encoding/json and gopkg.in/yaml.v3 encoders.
+func WithKeyOrder(order KeyOrder) Option {
+	return func(options *options) {
+		options.keyOrder = order
+	}
+}
+
+// WithHTMLEscaping is a functional option that allows you to specify whether the JSON encoder escapes
+// the <, > and & characters in the strings, for example < as \u003c. The escaping is enabled by default.
+// The YAML encoder never escapes the characters.
+func WithHTMLEscaping(enabled bool) Option {
+	return func(options *options) {
+		options.escapeHTML = enabled
+	}
+}
+
+// WithTrailingNewline is a functional option that allows you to specify whether the document ends with
+// a newline. The newline is written by default.
+func WithTrailingNewline(enabled bool) Option {
+	return func(options *options) {
+		options.trailingNewline = enabled
+	}
+}
+
+// WithFlowLists is a functional option that allows the YAML encoder to write the lists of at most maxItems
+// scalars in the flow style, for example [publish, subscribe]. By default, all lists are written in the block style.
+// The JSON encoder ignores the option.
+func WithFlowLists(maxItems int) Option {
+	return func(options *options) {
+		options.flowListItems = maxItems
+	}
+}
+
+func newOptions(indent int, defaultKeyOrder KeyOrder, opts []Option) options {
+	result := options{
+		indent:          indent,
+		defaultKeyOrder: defaultKeyOrder,
+		escapeHTML:      true,
+		trailingNewline: true,
+	}
+	for _, option := range opts {
+		option(&result)
+	}
+	return result
+}
+
 // ToJSON writes an AsyncAPI document in the JSON format encoding it into a stream.
+// The json.Number values are written exactly as decoded.
+//
+// See JSON to configure the encoding.
 func ToJSON(i interface{}, writer io.Writer) error {
-	return json.NewEncoder(writer).Encode(i)
+	return defaultJSON(i, writer)
 }
 
 // ToYaml writes an AsyncAPI document in the YAML format encoding it into a stream.
+// The json.Number values are written exactly as decoded, as plain YAML numbers.
+//
+// See YAML to configure the encoding.
 func ToYaml(i interface{}, writer io.Writer) error {
-	return yaml.NewEncoder(writer).Encode(i)
+	return defaultYAML(i, writer)
+}
+
+var (
+	defaultJSON = JSON()
+	defaultYAML = YAML()
+)
+
+// sortedMapKeys returns the keys of the mapping at the path in the order of the KeyOrder.
+func (o options) sortedMapKeys(path string, mapping map[string]interface{}) []string {
+	keys := make([]string, 0, len(mapping))
+	for key := range mapping {
+		keys = append(keys, key)
+	}
+	o.orderKeys(path, keys)
+	return keys
+}
+
+// orderKeys orders the keys of the mapping at the path with the KeyOrder of the encoder.
+func (o options) orderKeys(path string, keys []string) {
+	if o.keyOrder == nil {
+		o.defaultKeyOrder(path, keys)
+		return
+	}
+	o.keyOrder(path, keys)
+}
+
+// childPath returns the JSON pointer of the child of the path.
+func childPath(path, key string) string {
+	return path + "/" + strings.Replace(strings.Replace(key, "~", "~0", -1), "/", "~1", -1)
 }
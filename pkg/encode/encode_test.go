@@ -0,0 +1,286 @@
+package encode
+
+import (
+	"github.com/asyncapi/converter-go/pkg/decode"
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestExactNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		decode   func(interface{}, io.Reader) error
+		encode   func(interface{}, io.Writer) error
+		input    string
+		expected string
+	}{
+		{
+			name:     "json to json",
+			decode:   decode.FromJSON,
+			encode:   ToJSON,
+			input:    `{"example": 1234567890123456789, "enum": [1.50, -0.0, 1e400]}`,
+			expected: "{\"enum\":[1.50,-0.0,1e400],\"example\":1234567890123456789}\n",
+		},
+		{
+			name:     "json to yaml",
+			decode:   decode.FromJSONWithYamlFallback,
+			encode:   ToYaml,
+			input:    `{"example": 1234567890123456789, "enum": [1.50, 1E+2]}`,
+			expected: "enum:\n  - 1.50\n  - 1E+2\nexample: 1234567890123456789\n",
+		},
+		{
+			name:     "yaml to json",
+			decode:   decode.FromYaml,
+			encode:   ToJSON,
+			input:    "example: 18446744073709551616\nenum: [1.50, 0x10, '7']\n",
+			expected: "{\"enum\":[1.50,16,\"7\"],\"example\":18446744073709551616}\n",
+		},
+		{
+			name:     "yaml to yaml",
+			decode:   decode.FromYaml,
+			encode:   ToYaml,
+			input:    "example: 1234567890123456789\nenum: [1.50, '7']\n",
+			expected: "enum:\n  - 1.50\n  - \"7\"\nexample: 1234567890123456789\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var document interface{}
+			g.Expect(test.decode(&document, strings.NewReader(test.input))).To(Succeed())
+			var out bytes.Buffer
+			g.Expect(test.encode(&document, &out)).To(Succeed())
+			g.Expect(out.String()).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	document := map[string]interface{}{
+		"info":     map[string]interface{}{"title": "<Streetlights> & co", "version": "1.0.0"},
+		"asyncapi": "2.0.0",
+		"tags":     []interface{}{"a", "b"},
+	}
+	reversed := func(_ string, keys []string) {
+		SortedKeys("", keys)
+		for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
+			keys[i], keys[j] = keys[j], keys[i]
+		}
+	}
+	tests := []struct {
+		name     string
+		encode   func(interface{}, io.Writer) error
+		expected string
+	}{
+		{
+			name:     "json defaults",
+			encode:   JSON(),
+			expected: "{\"asyncapi\":\"2.0.0\",\"info\":{\"title\":\"\\u003cStreetlights\\u003e \\u0026 co\",\"version\":\"1.0.0\"},\"tags\":[\"a\",\"b\"]}\n",
+		},
+		{
+			name:   "json indented without html escaping and trailing newline",
+			encode: JSON(WithIndent(2), WithHTMLEscaping(false), WithTrailingNewline(false)),
+			expected: `{
+  "asyncapi": "2.0.0",
+  "info": {
+    "title": "<Streetlights> & co",
+    "version": "1.0.0"
+  },
+  "tags": [
+    "a",
+    "b"
+  ]
+}`,
+		},
+		{
+			name:     "json key order",
+			encode:   JSON(WithKeyOrder(reversed), WithHTMLEscaping(false)),
+			expected: "{\"tags\":[\"a\",\"b\"],\"info\":{\"version\":\"1.0.0\",\"title\":\"<Streetlights> & co\"},\"asyncapi\":\"2.0.0\"}\n",
+		},
+		{
+			name:     "yaml defaults",
+			encode:   YAML(),
+			expected: "asyncapi: 2.0.0\ninfo:\n    title: <Streetlights> & co\n    version: 1.0.0\ntags:\n  - a\n  - b\n",
+		},
+		{
+			name:     "yaml indented with flow lists and without trailing newline",
+			encode:   YAML(WithIndent(2), WithFlowLists(2), WithTrailingNewline(false)),
+			expected: "asyncapi: 2.0.0\ninfo:\n  title: <Streetlights> & co\n  version: 1.0.0\ntags: [a, b]",
+		},
+		{
+			name:     "yaml list longer than flow lists",
+			encode:   YAML(WithFlowLists(1), WithKeyOrder(reversed)),
+			expected: "tags:\n  - a\n  - b\ninfo:\n    version: 1.0.0\n    title: <Streetlights> & co\nasyncapi: 2.0.0\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var out bytes.Buffer
+			g.Expect(test.encode(document, &out)).To(Succeed())
+			g.Expect(out.String()).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestNaturalKeys(t *testing.T) {
+	g := NewWithT(t)
+	keys := []string{"server10", "b", "server2", "server1", "a10", "a9b", "server02"}
+	NaturalKeys("", keys)
+	g.Expect(keys).To(Equal([]string{"a9b", "a10", "b", "server1", "server2", "server02", "server10"}))
+}
+
+func TestKeyOrder_defaults(t *testing.T) {
+	document := map[string]interface{}{
+		"servers": map[string]interface{}{"server1": 1, "server10": 10, "server2": 2},
+	}
+	tests := []struct {
+		name     string
+		encode   func(interface{}, io.Writer) error
+		expected string
+	}{
+		{
+			name:     "json",
+			encode:   ToJSON,
+			expected: "{\"servers\":{\"server1\":1,\"server10\":10,\"server2\":2}}\n",
+		},
+		{
+			name:     "yaml",
+			encode:   ToYaml,
+			expected: "servers:\n    server1: 1\n    server2: 2\n    server10: 10\n",
+		},
+		{
+			name:     "yaml with options",
+			encode:   YAML(WithIndent(2), WithFlowLists(2)),
+			expected: "servers:\n  server1: 1\n  server2: 2\n  server10: 10\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var out bytes.Buffer
+			g.Expect(test.encode(document, &out)).To(Succeed())
+			g.Expect(out.String()).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestStandardEncoders(t *testing.T) {
+	data, err := ioutil.ReadFile("../converter/v2/testdata/output/slack-rtm.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var document interface{}
+	if err := decode.FromYaml(&document, bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		standard func(interface{}, io.Writer) error
+		custom   func(interface{}, io.Writer) error
+	}{
+		{
+			name:     "json",
+			standard: JSON(WithIndent(2), WithHTMLEscaping(false), WithTrailingNewline(false)),
+			custom:   JSON(WithIndent(2), WithHTMLEscaping(false), WithTrailingNewline(false), WithKeyOrder(SortedKeys)),
+		},
+		{
+			name:     "yaml",
+			standard: YAML(WithTrailingNewline(false)),
+			custom:   YAML(WithTrailingNewline(false), WithKeyOrder(NaturalKeys)),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var standard, custom bytes.Buffer
+			g.Expect(test.standard(document, &standard)).To(Succeed())
+			g.Expect(test.custom(document, &custom)).To(Succeed())
+			g.Expect(standard.String()).To(Equal(custom.String()))
+		})
+	}
+}
+
+func TestDefaultOutput(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/document.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		encode func(interface{}, io.Writer) error
+		golden string
+	}{
+		{
+			name:   "ToJSON",
+			encode: ToJSON,
+			golden: "testdata/default.json",
+		},
+		{
+			name:   "JSON",
+			encode: JSON(),
+			golden: "testdata/default.json",
+		},
+		{
+			name:   "ToYaml",
+			encode: ToYaml,
+			golden: "testdata/default.yaml",
+		},
+		{
+			name:   "YAML",
+			encode: YAML(),
+			golden: "testdata/default.yaml",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			expected, err := ioutil.ReadFile(test.golden)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			var document interface{}
+			g.Expect(decode.FromYaml(&document, bytes.NewReader(data))).To(Succeed())
+			var out bytes.Buffer
+			g.Expect(test.encode(document, &out)).To(Succeed())
+			g.Expect(out.String()).To(Equal(string(expected)))
+		})
+	}
+}
+
+func TestYAML_scalars(t *testing.T) {
+	g := NewWithT(t)
+	document := map[string]interface{}{
+		"bool":      true,
+		"null":      nil,
+		"float":     1.5,
+		"int":       7,
+		"quoted":    "true",
+		"multiline": "first\nsecond\n",
+		"time":      "1:20",
+	}
+	var out bytes.Buffer
+	g.Expect(YAML(WithIndent(2))(document, &out)).To(Succeed())
+	g.Expect(out.String()).To(Equal(`bool: true
+float: 1.5
+int: 7
+multiline: |
+  first
+  second
+"null": null
+quoted: "true"
+time: "1:20"
+`))
+}
+
+func TestYamlStream(t *testing.T) {
+	g := NewWithT(t)
+	var out bytes.Buffer
+	stream := NewYamlStream(&out, WithIndent(2), WithTrailingNewline(false))
+	g.Expect(stream.Encode(map[string]interface{}{"a": map[string]interface{}{"b": 1}})).To(Succeed())
+	g.Expect(stream.Encode(map[string]interface{}{"c": []interface{}{"d"}})).To(Succeed())
+	g.Expect(out.String()).To(Equal("a:\n  b: 1\n---\nc:\n- d\n"))
+}
@@ -0,0 +1,158 @@
+package encode
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// JSON returns a function that writes an AsyncAPI document in the JSON format configured with the options.
+// The returned function satisfies the Encode type of the converter.
+func JSON(opts ...Option) func(interface{}, io.Writer) error {
+	o := newOptions(0, SortedKeys, opts)
+	if o.keyOrder == nil && !o.canonical {
+		return o.encodeJSON
+	}
+	return func(v interface{}, writer io.Writer) error {
+		w := o.newJSONWriter(writer)
+		if err := w.write("", v, 0); err != nil {
+			return err
+		}
+		return w.close()
+	}
+}
+
+// encodeJSON writes the document with the standard encoder, which orders the keys like SortedKeys.
+func (o options) encodeJSON(v interface{}, writer io.Writer) error {
+	out := writer
+	var buf bytes.Buffer
+	if !o.trailingNewline {
+		out = &buf
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(o.escapeHTML)
+	if o.indent > 0 {
+		encoder.SetIndent("", strings.Repeat(" ", o.indent))
+	}
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	if o.trailingNewline {
+		return nil
+	}
+	_, err := writer.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	return err
+}
+
+// jsonWriter writes the JSON values directly to the buffered writer, indenting them like json.Indent.
+type jsonWriter struct {
+	options
+	writer  *bufio.Writer
+	leaf    bytes.Buffer
+	encoder *json.Encoder
+}
+
+func (o options) newJSONWriter(writer io.Writer) *jsonWriter {
+	w := &jsonWriter{
+		options: o,
+		writer:  bufio.NewWriter(writer),
+	}
+	w.encoder = json.NewEncoder(&w.leaf)
+	w.encoder.SetEscapeHTML(o.escapeHTML)
+	return w
+}
+
+// close writes the trailing newline and flushes the writer.
+func (w *jsonWriter) close() error {
+	if w.trailingNewline {
+		w.writer.WriteByte('\n')
+	}
+	return w.writer.Flush()
+}
+
+func (w *jsonWriter) write(path string, v interface{}, depth int) error {
+	switch v := v.(type) {
+	case *map[string]interface{}:
+		return w.write(path, *v, depth)
+	case *interface{}:
+		return w.write(path, *v, depth)
+	case map[string]interface{}:
+		if len(v) == 0 {
+			_, err := w.writer.WriteString("{}")
+			return err
+		}
+		w.writer.WriteByte('{')
+		for i, key := range w.sortedMapKeys(path, v) {
+			if err := w.writeMember(i, key, childPath(path, key), v[key], depth+1); err != nil {
+				return err
+			}
+		}
+		w.newline(depth)
+		return w.writer.WriteByte('}')
+	case []interface{}:
+		if len(v) == 0 {
+			_, err := w.writer.WriteString("[]")
+			return err
+		}
+		w.writer.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				w.writer.WriteByte(',')
+			}
+			w.newline(depth + 1)
+			if err := w.write(path+"/"+strconv.Itoa(i), item, depth+1); err != nil {
+				return err
+			}
+		}
+		w.newline(depth)
+		return w.writer.WriteByte(']')
+	default:
+		return w.writeValue(w.normalize(v), depth)
+	}
+}
+
+// writeMember writes the i-th member of an object at the depth.
+func (w *jsonWriter) writeMember(i int, key, path string, value interface{}, depth int) error {
+	if i > 0 {
+		w.writer.WriteByte(',')
+	}
+	w.newline(depth)
+	if err := w.writeValue(key, depth); err != nil {
+		return err
+	}
+	w.writer.WriteByte(':')
+	if w.indent > 0 {
+		w.writer.WriteByte(' ')
+	}
+	return w.write(path, value, depth)
+}
+
+// newline starts a new line indented to the depth, if the output is indented.
+func (w *jsonWriter) newline(depth int) {
+	if w.indent > 0 {
+		w.writer.WriteByte('\n')
+		w.writer.WriteString(strings.Repeat(" ", depth*w.indent))
+	}
+}
+
+// writeValue writes the value with the standard encoder, which adds a newline after the value.
+func (w *jsonWriter) writeValue(v interface{}, depth int) error {
+	w.leaf.Reset()
+	if err := w.encoder.Encode(v); err != nil {
+		return err
+	}
+	data := w.leaf.Bytes()[:w.leaf.Len()-1]
+	if w.indent == 0 || len(data) == 0 || data[0] != '{' && data[0] != '[' {
+		_, err := w.writer.Write(data)
+		return err
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, data, strings.Repeat(" ", depth*w.indent), strings.Repeat(" ", w.indent)); err != nil {
+		return err
+	}
+	_, err := indented.WriteTo(w.writer)
+	return err
+}
@@ -0,0 +1,207 @@
+package encode
+
+import (
+	"gopkg.in/yaml.v3"
+
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// base60FloatRegexp matches the strings that YAML 1.1 parsers read as sexagesimal numbers, for example 1:20.
+// They are quoted like the YAML encoder does.
+var base60FloatRegexp = regexp.MustCompile(`^[-+]?[0-9][0-9_]*(?::[0-5]?[0-9])+(?:\.[0-9_]*)?$`)
+
+// YAML returns a function that writes an AsyncAPI document in the YAML format configured with the options.
+// The returned function satisfies the Encode type of the converter.
+func YAML(opts ...Option) func(interface{}, io.Writer) error {
+	o := newOptions(4, NaturalKeys, opts)
+	if o.keyOrder == nil && !o.canonical && o.flowListItems == 0 {
+		return func(v interface{}, writer io.Writer) error {
+			return o.encodeYaml(yamlValue(v), writer)
+		}
+	}
+	return func(v interface{}, writer io.Writer) error {
+		node, err := o.yamlNode("", v)
+		if err != nil {
+			return err
+		}
+		return o.encodeYaml(node, writer)
+	}
+}
+
+// encodeYaml writes the value with the standard encoder, which orders the keys like NaturalKeys.
+func (o options) encodeYaml(v interface{}, writer io.Writer) error {
+	out := writer
+	var buf bytes.Buffer
+	if !o.trailingNewline {
+		out = &buf
+	}
+	encoder := yaml.NewEncoder(out)
+	encoder.SetIndent(o.indent)
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	if o.trailingNewline {
+		return nil
+	}
+	_, err := writer.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	return err
+}
+
+// yamlValue returns a copy of the value with the json.Number values replaced with YAML number nodes,
+// as the YAML encoder writes them as strings otherwise.
+func yamlValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *map[string]interface{}:
+		return yamlValue(*v)
+	case *interface{}:
+		return yamlValue(*v)
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = yamlValue(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = yamlValue(item)
+		}
+		return result
+	case json.Number:
+		return numberNode(v)
+	default:
+		return v
+	}
+}
+
+// yamlNode returns the YAML node of the value with the keys of the mappings in the order of the KeyOrder.
+func (o options) yamlNode(path string, v interface{}) (*yaml.Node, error) {
+	switch v := o.normalize(v).(type) {
+	case *map[string]interface{}:
+		return o.yamlNode(path, *v)
+	case *interface{}:
+		return o.yamlNode(path, *v)
+	case map[string]interface{}:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, key := range o.sortedMapKeys(path, v) {
+			value, err := o.yamlNode(childPath(path, key), v[key])
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, stringNode(key), value)
+		}
+		return node, nil
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if o.isFlowList(v) {
+			node.Style = yaml.FlowStyle
+		}
+		for i, item := range v {
+			value, err := o.yamlNode(path+"/"+strconv.Itoa(i), item)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, value)
+		}
+		return node, nil
+	case nil:
+		return scalarNode("!!null", "null"), nil
+	case string:
+		return stringNode(v), nil
+	case bool:
+		return scalarNode("!!bool", strconv.FormatBool(v)), nil
+	case json.Number:
+		return numberNode(v), nil
+	case int:
+		return scalarNode("!!int", strconv.Itoa(v)), nil
+	case int64:
+		return scalarNode("!!int", strconv.FormatInt(v, 10)), nil
+	case uint64:
+		return scalarNode("!!int", strconv.FormatUint(v, 10)), nil
+	case float64:
+		return scalarNode("!!float", formatFloat(v)), nil
+	default:
+		return marshalNode(v)
+	}
+}
+
+// isFlowList reports whether the list is short enough to be written in the flow style and contains scalars only.
+func (o options) isFlowList(list []interface{}) bool {
+	if len(list) == 0 || len(list) > o.flowListItems {
+		return false
+	}
+	for _, item := range list {
+		switch item.(type) {
+		case map[string]interface{}, []interface{}:
+			return false
+		case string:
+			if strings.Contains(item.(string), "\n") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// numberNode returns the node of the number written exactly as decoded, as a plain YAML number.
+func numberNode(number json.Number) *yaml.Node {
+	if strings.ContainsAny(string(number), ".eE") {
+		return scalarNode("!!float", string(number))
+	}
+	return scalarNode("!!int", string(number))
+}
+
+func scalarNode(tag, value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+}
+
+// stringNode returns the node of the string in the style the YAML encoder writes the strings with.
+func stringNode(s string) *yaml.Node {
+	node := scalarNode("!!str", s)
+	switch {
+	case strings.Contains(s, "\n"):
+		node.Style = yaml.LiteralStyle
+	case base60FloatRegexp.MatchString(s):
+		node.Style = yaml.DoubleQuotedStyle
+	}
+	return node
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return ".inf"
+	case math.IsInf(f, -1):
+		return "-.inf"
+	case math.IsNaN(f):
+		return ".nan"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
+
+// marshalNode returns the node of the value of any other type, as the YAML encoder writes it.
+func marshalNode(v interface{}) (*yaml.Node, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	if len(document.Content) != 1 {
+		return nil, fmt.Errorf("yaml: cannot encode %T", v)
+	}
+	return document.Content[0], nil
+}
@@ -11,6 +11,7 @@ const (
 	errInvalidDocument
 	errUnsupportedAsyncapiVersion
 	errDocumentVersionUpToDate
+	errLimitExceeded
 )
 
 // Error represents the conversion error.
@@ -23,7 +24,28 @@ func (err Error) Error() string {
 	return err.msg
 }
 
+// DocumentError is returned by the conversion of a stream of documents when one of the documents fails.
+// The Is functions of the package check the error of the document.
+type DocumentError struct {
+	// Index is the position of the failed document in the stream, starting at 0.
+	Index int
+	// Err is the error of the document.
+	Err error
+}
+
+func (err DocumentError) Error() string {
+	return fmt.Sprintf("document %d: %s", err.Index, err.Err)
+}
+
+// Cause returns the error of the document, so errors.Cause of github.com/pkg/errors returns it as well.
+func (err DocumentError) Cause() error {
+	return err.Err
+}
+
 func isErrorType(errType errType, err error) bool {
+	if documentErr, ok := err.(DocumentError); ok {
+		err = documentErr.Err
+	}
 	if err, ok := err.(Error); ok {
 		return err.errType == errType
 	}
@@ -62,6 +84,14 @@ func IsDocumentVersionUpToDate(err error) bool {
 	return isErrorType(errDocumentVersionUpToDate, err)
 }
 
+// IsLimitExceeded returns true if err is the LimitExceeded error,
+// otherwise it returns false.
+//
+// See NewLimitExceeded.
+func IsLimitExceeded(err error) bool {
+	return isErrorType(errLimitExceeded, err)
+}
+
 func newError(errType errType, msg string) Error {
 	return Error{
 		errType: errType,
@@ -83,6 +113,13 @@ func NewInvalidDocument() Error {
 	return newError(errInvalidDocument, "asyncapi: unable to decode document")
 }
 
+// NewInvalidDocumentWithCause creates a new invalid document error with the message of the decoding error.
+// This error is returned by the AsyncAPI Converter when a document cannot be parsed.
+func NewInvalidDocumentWithCause(cause error) Error {
+	msg := fmt.Sprintf("asyncapi: unable to decode document: %s", cause)
+	return newError(errInvalidDocument, msg)
+}
+
 // NewUnsupportedAsyncapiVersion creates a new unsupported AsyncAPI version error.
 // This error is returned when the AsyncAPI converter does not recognize the version of the
 // converted AsyncAPI document.
@@ -97,3 +134,11 @@ func NewDocumentVersionUpToDate(context interface{}) Error {
 	msg := fmt.Sprintf("asyncapi: document is already in version: %v", context)
 	return newError(errDocumentVersionUpToDate, msg)
 }
+
+// NewLimitExceeded creates a new limit exceeded error.
+// This error is returned when a decoded document exceeds one of the decoding limits,
+// such as the maximum size, nesting depth or alias expansion.
+func NewLimitExceeded(limit string, max interface{}) Error {
+	msg := fmt.Sprintf("asyncapi: document exceeds the maximum %s of %v", limit, max)
+	return newError(errLimitExceeded, msg)
+}
@@ -39,6 +39,11 @@ func TestIsInvalidPropertyErr(t *testing.T) {
 			error:    NewDocumentVersionUpToDate("test"),
 			expected: false,
 		},
+		{
+			name:     "LimitExceeded",
+			error:    NewLimitExceeded("depth", 1),
+			expected: false,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -67,6 +72,11 @@ func TestIsErrInvalidDocumentErr(t *testing.T) {
 			error:    NewInvalidDocument(),
 			expected: true,
 		},
+		{
+			name:     "ErrInvalidDocument with cause",
+			error:    NewInvalidDocumentWithCause(errors.New("test")),
+			expected: true,
+		},
 		{
 			name:     "ErrUnsupportedAsyncapiVersion",
 			error:    NewUnsupportedAsyncapiVersion("test"),
@@ -77,6 +87,11 @@ func TestIsErrInvalidDocumentErr(t *testing.T) {
 			error:    NewDocumentVersionUpToDate("test"),
 			expected: false,
 		},
+		{
+			name:     "LimitExceeded",
+			error:    NewLimitExceeded("depth", 1),
+			expected: false,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -115,6 +130,11 @@ func TestIsErrUnsupportedAsyncapiVersionErr(t *testing.T) {
 			error:    NewDocumentVersionUpToDate("test"),
 			expected: false,
 		},
+		{
+			name:     "LimitExceeded",
+			error:    NewLimitExceeded("depth", 1),
+			expected: false,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -153,6 +173,11 @@ func TestDocumentVersionUpToDateErr(t *testing.T) {
 			error:    NewDocumentVersionUpToDate("test"),
 			expected: true,
 		},
+		{
+			name:     "LimitExceeded",
+			error:    NewLimitExceeded("depth", 1),
+			expected: false,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -164,3 +189,50 @@ func TestDocumentVersionUpToDateErr(t *testing.T) {
 		})
 	}
 }
+
+func TestIsLimitExceededErr(t *testing.T) {
+	tests := []struct {
+		name     string
+		error    error
+		expected bool
+	}{
+		{
+			name:     "ErrInvalidProperty",
+			error:    NewInvalidProperty("test"),
+			expected: false,
+		},
+		{
+			name:     "ErrInvalidDocument",
+			error:    NewInvalidDocument(),
+			expected: false,
+		},
+		{
+			name:     "DocumentVersionUpToDate",
+			error:    NewDocumentVersionUpToDate("test"),
+			expected: false,
+		},
+		{
+			name:     "LimitExceeded",
+			error:    NewLimitExceeded("depth", 1),
+			expected: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(IsLimitExceeded(test.error)).To(Equal(test.expected))
+			if actual, ok := test.error.(Error); ok {
+				g.Expect(actual.Error()).ToNot(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestDocumentError(t *testing.T) {
+	g := NewWithT(t)
+	err := DocumentError{Index: 2, Err: NewInvalidProperty("topics")}
+	g.Expect(err.Error()).To(Equal("document 2: asyncapi: error invalid property topics"))
+	g.Expect(IsInvalidProperty(err)).To(BeTrue())
+	g.Expect(IsInvalidDocument(err)).To(BeFalse())
+	g.Expect(err.Cause()).To(Equal(NewInvalidProperty("topics")))
+}